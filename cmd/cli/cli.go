@@ -14,6 +14,12 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// snapshotPublishHelp states the limit of the tombstones, deletions are recorded since
+// migration 009 and earlier ones only as far as the outbox still holds their events.
+const snapshotPublishHelp = "publish all profiles to the compacted snapshot topic. " +
+	"Tombstones are written for the deletions recorded since migration 009, profiles deleted " +
+	"before it get one only if their profile.deleted event was still in the outbox when 011 ran"
+
 func Run(args []string) bool {
 	cfg, err := cmd.LoadConfig()
 	if err != nil {
@@ -51,6 +57,14 @@ func Run(args []string) bool {
 		migrateCmd     = service.Command("migrate", "migrate command")
 		migrateUpCmd   = migrateCmd.Command("up", "migrate db up")
 		migrateDownCmd = migrateCmd.Command("down", "migrate db down")
+
+		cleanCmd        = service.Command("clean", "clean command")
+		cleanUploadsCmd = cleanCmd.Command("uploads", "delete abandoned temp uploads once")
+
+		snapshotCmd         = service.Command("snapshot", "snapshot command")
+		snapshotPublishCmd  = snapshotCmd.Command("publish", snapshotPublishHelp)
+		snapshotBatchSize   = snapshotPublishCmd.Flag("batch-size", "rows published per transaction").Default("500").Uint()
+		snapshotResumeToken = snapshotPublishCmd.Flag("resume", "resume token reported by a previous run").String()
 	)
 
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
@@ -71,6 +85,10 @@ func Run(args []string) bool {
 		err = migrations.MigrateUp(ctx, cfg.Database.SQL.URL)
 	case migrateDownCmd.FullCommand():
 		err = migrations.MigrateDown(ctx, cfg.Database.SQL.URL)
+	case cleanUploadsCmd.FullCommand():
+		err = cmd.CleanUploads(ctx, cfg, log)
+	case snapshotPublishCmd.FullCommand():
+		err = cmd.PublishSnapshot(ctx, cfg, log, *snapshotBatchSize, *snapshotResumeToken)
 	default:
 		log.Errorf("unknown command %s", command)
 		return false
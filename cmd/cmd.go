@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/credentials"
@@ -13,12 +15,18 @@ import (
 	"github.com/netbill/pgdbx"
 	"github.com/netbill/profiles-svc/internal/bucket"
 	"github.com/netbill/profiles-svc/internal/core/modules/profile"
+	"github.com/netbill/profiles-svc/internal/imaging"
+	"github.com/netbill/profiles-svc/internal/janitor"
 	"github.com/netbill/profiles-svc/internal/messenger"
+	"github.com/netbill/profiles-svc/internal/messenger/contracts"
 	"github.com/netbill/profiles-svc/internal/messenger/inbound"
 	"github.com/netbill/profiles-svc/internal/messenger/outbound"
+	"github.com/netbill/profiles-svc/internal/moderation"
 	"github.com/netbill/profiles-svc/internal/repository"
 	"github.com/netbill/profiles-svc/internal/repository/pg"
 	"github.com/netbill/profiles-svc/internal/rest/middlewares"
+	"github.com/netbill/profiles-svc/internal/rest/responses"
+	"github.com/netbill/profiles-svc/internal/storage"
 	"github.com/netbill/profiles-svc/internal/tokenmanager"
 	"github.com/netbill/restkit"
 
@@ -41,57 +49,32 @@ func StartServices(ctx context.Context, cfg Config, log *logium.Logger, wg *sync
 	}
 	db := pgdbx.NewDB(pool)
 
-	awsCfg := aws.Config{
-		Region: cfg.S3.AWS.Region,
-		Credentials: credentials.NewStaticCredentialsProvider(
-			cfg.S3.AWS.AccessKeyID,
-			cfg.S3.AWS.SecretAccessKey,
-			"",
-		),
+	objects, fsStorage, err := newStorage(cfg)
+	if err != nil {
+		log.Fatal("failed to create object storage", "error", err)
 	}
 
-	s3Client := s3.NewFromConfig(awsCfg)
-	presignClient := s3.NewPresignClient(s3Client)
-
-	awsS3 := awsx.New(
-		cfg.S3.AWS.BucketName,
-		s3Client,
-		presignClient,
-	)
+	profileSvc := newProfileModule(cfg, log, db, objects)
 
-	profileAvatarValidator := &awsx.ImgObjectValidator{
-		AllowedContentTypes: cfg.S3.Upload.Profile.Avatar.AllowedContentTypes,
-		AllowedFormats:      cfg.S3.Upload.Profile.Avatar.AllowedFormats,
-		MaxWidth:            cfg.S3.Upload.Profile.Avatar.MaxWidth,
-		MaxHeight:           cfg.S3.Upload.Profile.Avatar.MaxHeight,
-		ContentLengthMax:    cfg.S3.Upload.Profile.Avatar.ContentLengthMax,
-	}
-
-	s3Bucket := bucket.New(bucket.Config{
-		S3:                     awsS3,
-		ProfileAvatarValidator: profileAvatarValidator,
-		UploadTokensTTL: bucket.UploadTokensTTL{
-			ProfileAvatar: cfg.S3.Upload.Token.TTL.Profile,
-		},
+	mediaURLs, err := responses.NewMediaURLs(log, objects, responses.MediaURLsConfig{
+		Mode:          cfg.Storage.URLs.Mode,
+		PublicBaseURL: cfg.Storage.URLs.PublicBaseURL,
+		TTL:           cfg.Storage.URLs.TTL,
 	})
-
-	profilesSqlQ := pg.NewProfilesQ(db)
-	transactionSqlQ := pg.NewTransaction(db)
-	repo := repository.New(transactionSqlQ, profilesSqlQ)
-
-	kafkaOutbound := outbound.New(log, db)
-
-	tokenManager := tokenmanager.New(cfg.Service.Name, cfg.S3.Upload.Token.TTL.Profile)
-
-	profileSvc := profile.New(repo, kafkaOutbound, tokenManager, s3Bucket)
+	if err != nil {
+		log.Fatal("invalid media urls config", "error", err)
+	}
 
 	responser := restkit.NewResponser()
-	ctrl := controller.New(log, responser, profileSvc)
+	ctrl := controller.New(log, responser, profileSvc, mediaURLs)
 	mdll := middlewares.New(log, responser, middlewares.Config{
 		AccountAccessSK: cfg.Auth.Account.Token.Access.SecretKey,
 		UploadFilesSK:   cfg.S3.Upload.Token.SecretKey,
 	})
 	router := rest.New(log, mdll, ctrl)
+	if fsStorage != nil {
+		router.MountStorage(fsStorage.MountPath(), fsStorage.Handler())
+	}
 
 	msgx := messenger.New(log, db, cfg.Kafka.Brokers...)
 
@@ -109,5 +92,274 @@ func StartServices(ctx context.Context, cfg Config, log *logium.Logger, wg *sync
 
 	run(func() { msgx.RunProducer(ctx) })
 
-	run(func() { msgx.RunConsumer(ctx, inbound.New(log, profileSvc)) })
+	run(func() { msgx.RunConsumer(ctx, inbound.New(log, profileSvc, msgx, inbound.DefaultVersions())) })
+
+	run(func() {
+		janitor.New(log, profileSvc, janitor.Config{
+			Interval: cfg.Janitor.Uploads.Interval,
+			MaxAge:   cfg.Janitor.Uploads.MaxAge,
+		}).Run(ctx)
+	})
+}
+
+// CleanUploads makes a single sweep of abandoned uploads.
+func CleanUploads(ctx context.Context, cfg Config, log *logium.Logger) error {
+	pool, err := pgxpool.New(ctx, cfg.Database.SQL.URL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer pool.Close()
+
+	objects, _, err := newStorage(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create object storage: %w", err)
+	}
+
+	_, err = janitor.New(log, newProfileModule(cfg, log, pgdbx.NewDB(pool), objects), janitor.Config{
+		Interval: cfg.Janitor.Uploads.Interval,
+		MaxAge:   cfg.Janitor.Uploads.MaxAge,
+	}).RunOnce(ctx)
+
+	return err
+}
+
+// PublishSnapshot writes every profile and a tombstone for every deleted profile
+// to the snapshot topic through the outbox, the running service delivers them.
+func PublishSnapshot(ctx context.Context, cfg Config, log *logium.Logger, batchSize uint, resumeToken string) error {
+	pool, err := pgxpool.New(ctx, cfg.Database.SQL.URL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer pool.Close()
+
+	objects, _, err := newStorage(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create object storage: %w", err)
+	}
+
+	res, err := newProfileModule(cfg, log, pgdbx.NewDB(pool), objects).PublishSnapshot(ctx, profile.SnapshotParams{
+		BatchSize:   batchSize,
+		ResumeToken: resumeToken,
+		Progress: func(p profile.SnapshotProgress) {
+			log.Infof(
+				"snapshot progress: %d profiles, %d tombstones, resume token: %s",
+				p.Profiles, p.Tombstones, p.ResumeToken,
+			)
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("snapshot stopped, resume with token %s: %w", res.ResumeToken, err)
+	}
+
+	log.Infof("snapshot published: %d profiles, %d tombstones", res.Profiles, res.Tombstones)
+
+	return nil
+}
+
+type objectStorage interface {
+	bucket.Storage
+	PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// newStorage creates the object storage selected by storage.driver,
+// the fs storage is returned separately as its handler has to be mounted.
+func newStorage(cfg Config) (objectStorage, *storage.FS, error) {
+	switch cfg.Storage.Driver {
+	case "", StorageDriverS3:
+		awsCfg := aws.Config{
+			Region: cfg.S3.AWS.Region,
+			Credentials: credentials.NewStaticCredentialsProvider(
+				cfg.S3.AWS.AccessKeyID,
+				cfg.S3.AWS.SecretAccessKey,
+				cfg.S3.AWS.SessionToken,
+			),
+		}
+
+		s3Client := s3.NewFromConfig(awsCfg, s3Endpoint(cfg.S3.AWS.Endpoint, cfg.S3.AWS.UsePathStyle))
+
+		presignEndpoint := cfg.S3.AWS.PublicURL
+		if presignEndpoint == "" {
+			presignEndpoint = cfg.S3.AWS.Endpoint
+		}
+		presignClient := s3.NewPresignClient(
+			s3.NewFromConfig(awsCfg, s3Endpoint(presignEndpoint, cfg.S3.AWS.UsePathStyle)),
+		)
+
+		return storage.NewS3(cfg.S3.AWS.BucketName, s3Client, presignClient), nil, nil
+	case StorageDriverFS:
+		fsStorage, err := storage.NewFS(storage.FSConfig{
+			Root:          cfg.Storage.FS.Root,
+			PublicURL:     cfg.Storage.FS.PublicURL,
+			SecretKey:     cfg.Storage.FS.SecretKey,
+			MaxObjectSize: cfg.Storage.FS.MaxObjectSize,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return fsStorage, fsStorage, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown storage driver %q", cfg.Storage.Driver)
+	}
+}
+
+// s3Endpoint points the s3 client at an S3-compatible endpoint, an empty endpoint keeps AWS.
+func s3Endpoint(endpoint string, usePathStyle bool) func(o *s3.Options) {
+	return func(o *s3.Options) {
+		if endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+		}
+		o.UsePathStyle = usePathStyle
+	}
+}
+
+func newModerator(cfg Config) (bucket.AvatarModerator, error) {
+	switch cfg.Moderation.Driver {
+	case "", ModerationDriverNoop:
+		return moderation.Noop{}, nil
+	case ModerationDriverWebhook:
+		return moderation.NewWebhook(moderation.WebhookConfig{
+			URL:       cfg.Moderation.Webhook.URL,
+			SecretKey: cfg.Moderation.Webhook.SecretKey,
+			Timeout:   cfg.Moderation.Webhook.Timeout,
+		})
+	default:
+		return nil, fmt.Errorf("unknown moderation driver %q", cfg.Moderation.Driver)
+	}
+}
+
+func newOutboundConfig(cfg Config) (outbound.Config, error) {
+	versions := cfg.Kafka.Outbound.ProfilesVersions
+
+	byTopic := make(map[string]string, len(cfg.Kafka.Outbound.Codecs))
+	for _, c := range cfg.Kafka.Outbound.Codecs {
+		// the evebox outbox keeps payloads as jsonb, a protobuf payload can not be stored
+		if c.Codec == contracts.CodecProtobuf {
+			return outbound.Config{}, fmt.Errorf("codec for topic %s: protobuf payloads are not supported by the outbox yet", c.Topic)
+		}
+		byTopic[c.Topic] = c.Codec
+	}
+
+	codecs, err := contracts.NewCodecs(byTopic)
+	if err != nil {
+		return outbound.Config{}, err
+	}
+
+	res := outbound.Config{Codecs: codecs}
+	if len(versions) == 0 {
+		res.ProfilesV1 = true
+		return res, nil
+	}
+
+	for _, v := range versions {
+		switch v {
+		case 1:
+			res.ProfilesV1 = true
+		case 2:
+			res.ProfilesV2 = true
+		default:
+			return outbound.Config{}, fmt.Errorf("unknown profiles topic version %d", v)
+		}
+	}
+
+	return res, nil
+}
+
+func newProfileModule(cfg Config, log *logium.Logger, db *pgdbx.DB, objects bucket.Storage) *profile.Module {
+	profileAvatarValidator := &awsx.ImgObjectValidator{
+		AllowedContentTypes: cfg.S3.Upload.Profile.Avatar.AllowedContentTypes,
+		AllowedFormats:      cfg.S3.Upload.Profile.Avatar.AllowedFormats,
+		MaxWidth:            cfg.S3.Upload.Profile.Avatar.MaxWidth,
+		MaxHeight:           cfg.S3.Upload.Profile.Avatar.MaxHeight,
+		ContentLengthMax:    cfg.S3.Upload.Profile.Avatar.ContentLengthMax,
+	}
+
+	profileAvatarVariants := bucket.ImageVariants{
+		Sizes:   cfg.S3.Upload.Profile.Avatar.Variants.Sizes,
+		Formats: cfg.S3.Upload.Profile.Avatar.Variants.Formats,
+	}
+	if err := profileAvatarVariants.Validate(); err != nil {
+		log.Fatal("invalid profile avatar variants config", "error", err)
+	}
+
+	profileBannerValidator := &awsx.ImgObjectValidator{
+		AllowedContentTypes: cfg.S3.Upload.Profile.Banner.AllowedContentTypes,
+		AllowedFormats:      cfg.S3.Upload.Profile.Banner.AllowedFormats,
+		MaxWidth:            cfg.S3.Upload.Profile.Banner.MaxWidth,
+		MaxHeight:           cfg.S3.Upload.Profile.Banner.MaxHeight,
+		ContentLengthMax:    cfg.S3.Upload.Profile.Banner.ContentLengthMax,
+	}
+
+	profileBannerAspectRatio := bucket.AspectRatio{
+		Min: cfg.S3.Upload.Profile.Banner.AspectRatio.Min,
+		Max: cfg.S3.Upload.Profile.Banner.AspectRatio.Max,
+	}
+	if err := profileBannerAspectRatio.Validate(); err != nil {
+		log.Fatal("invalid profile banner aspect ratio config", "error", err)
+	}
+
+	moderator, err := newModerator(cfg)
+	if err != nil {
+		log.Fatal("invalid moderation config", "error", err)
+	}
+
+	s3Bucket := bucket.New(bucket.Config{
+		Storage:   objects,
+		Moderator: moderator,
+		ProfileAvatar: bucket.MediaConfig{
+			Validator: profileAvatarValidator,
+			Variants:  profileAvatarVariants,
+			Decoding: bucket.ImageDecoding{
+				Full:     cfg.S3.Upload.Profile.Avatar.FullValidation,
+				MaxBytes: cfg.S3.Upload.Profile.Avatar.ContentLengthMax,
+				Limits: imaging.Limits{
+					MaxPixels: cfg.S3.Upload.Profile.Avatar.MaxPixels,
+					MaxFrames: cfg.S3.Upload.Profile.Avatar.MaxFrames,
+				},
+			},
+		},
+		ProfileBanner: bucket.MediaConfig{
+			Validator:   profileBannerValidator,
+			AspectRatio: profileBannerAspectRatio,
+			Decoding: bucket.ImageDecoding{
+				Full:     cfg.S3.Upload.Profile.Banner.FullValidation,
+				MaxBytes: cfg.S3.Upload.Profile.Banner.ContentLengthMax,
+				Limits: imaging.Limits{
+					MaxPixels: cfg.S3.Upload.Profile.Banner.MaxPixels,
+					MaxFrames: cfg.S3.Upload.Profile.Banner.MaxFrames,
+				},
+			},
+		},
+		UploadTokensTTL: bucket.UploadTokensTTL{
+			ProfileMedia: cfg.S3.Upload.Token.TTL.Profile,
+		},
+	})
+
+	profilesSqlQ := pg.NewProfilesQ(db)
+	transactionSqlQ := pg.NewTransaction(db)
+	uploadSessionsSqlQ := pg.NewUploadSessionsQ(db)
+	profileAvatarsSqlQ := pg.NewProfileAvatarsQ(db)
+	profileTombstonesSqlQ := pg.NewProfileTombstonesQ(db)
+	repo := repository.New(
+		transactionSqlQ,
+		profilesSqlQ,
+		uploadSessionsSqlQ,
+		profileAvatarsSqlQ,
+		profileTombstonesSqlQ,
+	)
+
+	outboundConfig, err := newOutboundConfig(cfg)
+	if err != nil {
+		log.Fatal("invalid kafka outbound config", "error", err)
+	}
+
+	kafkaOutbound := outbound.New(log, db, outboundConfig)
+
+	tokenManager := tokenmanager.New(cfg.Service.Name, cfg.S3.Upload.Token.TTL.Profile)
+
+	return profile.New(log, repo, kafkaOutbound, tokenManager, s3Bucket, profile.Config{
+		BannedWords:       cfg.Profile.Validation.BannedWords,
+		UploadSessionTTL:  cfg.S3.Upload.Token.TTL.Profile,
+		AvatarHistorySize: cfg.Profile.AvatarHistory.Size,
+	})
 }
@@ -31,6 +31,18 @@ type DatabaseConfig struct {
 
 type KafkaConfig struct {
 	Brokers []string `mapstructure:"brokers"`
+
+	Outbound struct {
+		// ProfilesVersions are the profiles topic versions events are written to,
+		// 1 for profiles.v1 and 2 for profiles.v2, only 1 when it is empty.
+		ProfilesVersions []int `mapstructure:"profiles_versions"`
+
+		// Codecs are the payload encodings by topic, json for topics not listed.
+		Codecs []struct {
+			Topic string `mapstructure:"topic"`
+			Codec string `mapstructure:"codec"`
+		} `mapstructure:"codecs"`
+	} `mapstructure:"outbound"`
 }
 
 type AuthConfig struct {
@@ -49,6 +61,15 @@ type S3Config struct {
 		Region          string `mapstructure:"region"`
 		AccessKeyID     string `mapstructure:"access_key_id"`
 		SecretAccessKey string `mapstructure:"secret_access_key"`
+		SessionToken    string `mapstructure:"session_token"`
+
+		// Endpoint replaces the AWS endpoint, e.g. for MinIO or Ceph.
+		Endpoint string `mapstructure:"endpoint"`
+		// UsePathStyle addresses the bucket as endpoint/bucket instead of bucket.endpoint.
+		UsePathStyle bool `mapstructure:"use_path_style"`
+		// PublicURL is the endpoint clients reach the storage at, presigned links
+		// are signed against it. Endpoint is used when it is empty.
+		PublicURL string `mapstructure:"public_url"`
 	} `mapstructure:"aws"`
 
 	Upload struct {
@@ -66,18 +87,97 @@ type S3Config struct {
 				MaxWidth            uint     `mapstructure:"max_width"`
 				MaxHeight           uint     `mapstructure:"max_height"`
 				ContentLengthMax    uint     `mapstructure:"content_length_max"`
+				FullValidation      bool     `mapstructure:"full_validation"`
+				MaxPixels           uint64   `mapstructure:"max_pixels"`
+				MaxFrames           int      `mapstructure:"max_frames"`
+				Variants            struct {
+					Sizes   []uint   `mapstructure:"sizes"`
+					Formats []string `mapstructure:"formats"`
+				} `mapstructure:"variants"`
 			} `mapstructure:"avatar"`
+			Banner struct {
+				AllowedContentTypes []string `mapstructure:"allowed_content_types"`
+				AllowedFormats      []string `mapstructure:"allowed_formats"`
+				MaxWidth            uint     `mapstructure:"max_width"`
+				MaxHeight           uint     `mapstructure:"max_height"`
+				ContentLengthMax    uint     `mapstructure:"content_length_max"`
+				FullValidation      bool     `mapstructure:"full_validation"`
+				MaxPixels           uint64   `mapstructure:"max_pixels"`
+				MaxFrames           int      `mapstructure:"max_frames"`
+				AspectRatio         struct {
+					Min float64 `mapstructure:"min"`
+					Max float64 `mapstructure:"max"`
+				} `mapstructure:"aspect_ratio"`
+			} `mapstructure:"banner"`
 		} `mapstructure:"profile"`
 	} `mapstructure:"upload"`
 }
 
+type ProfileConfig struct {
+	Validation struct {
+		BannedWords []string `mapstructure:"banned_words"`
+	} `mapstructure:"validation"`
+	AvatarHistory struct {
+		Size uint `mapstructure:"size"`
+	} `mapstructure:"avatar_history"`
+}
+
+const (
+	StorageDriverS3 = "s3"
+	StorageDriverFS = "fs"
+)
+
+type StorageConfig struct {
+	// Driver is s3 or fs, s3 is used when it is empty.
+	Driver string `mapstructure:"driver"`
+	FS     struct {
+		Root          string `mapstructure:"root"`
+		PublicURL     string `mapstructure:"public_url"`
+		SecretKey     string `mapstructure:"secret_key"`
+		MaxObjectSize int64  `mapstructure:"max_object_size"`
+	} `mapstructure:"fs"`
+
+	// URLs controls how stored media keys are exposed in responses.
+	URLs struct {
+		Mode          string        `mapstructure:"mode"`
+		PublicBaseURL string        `mapstructure:"public_base_url"`
+		TTL           time.Duration `mapstructure:"ttl"`
+	} `mapstructure:"urls"`
+}
+
+const (
+	ModerationDriverNoop    = "noop"
+	ModerationDriverWebhook = "webhook"
+)
+
+type ModerationConfig struct {
+	// Driver is noop or webhook, noop is used when it is empty.
+	Driver  string `mapstructure:"driver"`
+	Webhook struct {
+		URL       string        `mapstructure:"url"`
+		SecretKey string        `mapstructure:"secret_key"`
+		Timeout   time.Duration `mapstructure:"timeout"`
+	} `mapstructure:"webhook"`
+}
+
+type JanitorConfig struct {
+	Uploads struct {
+		Interval time.Duration `mapstructure:"interval"`
+		MaxAge   time.Duration `mapstructure:"max_age"`
+	} `mapstructure:"uploads"`
+}
+
 type Config struct {
-	Log      LogConfig      `mapstructure:"log"`
-	Rest     RestConfig     `mapstructure:"rest"`
-	Auth     AuthConfig     `mapstructure:"auth"`
-	Kafka    KafkaConfig    `mapstructure:"kafka"`
-	Database DatabaseConfig `mapstructure:"database"`
-	S3       S3Config       `mapstructure:"s3"`
+	Log        LogConfig        `mapstructure:"log"`
+	Rest       RestConfig       `mapstructure:"rest"`
+	Auth       AuthConfig       `mapstructure:"auth"`
+	Kafka      KafkaConfig      `mapstructure:"kafka"`
+	Database   DatabaseConfig   `mapstructure:"database"`
+	S3         S3Config         `mapstructure:"s3"`
+	Storage    StorageConfig    `mapstructure:"storage"`
+	Profile    ProfileConfig    `mapstructure:"profile"`
+	Moderation ModerationConfig `mapstructure:"moderation"`
+	Janitor    JanitorConfig    `mapstructure:"janitor"`
 }
 
 func LoadConfig() (Config, error) {
@@ -97,5 +197,24 @@ func LoadConfig() (Config, error) {
 		return Config{}, fmt.Errorf("error unmarshalling config: %s", err)
 	}
 
+	if err := config.validate(); err != nil {
+		return Config{}, fmt.Errorf("invalid config: %w", err)
+	}
+
 	return config, nil
 }
+
+// validate rejects settings that silently disable a limit. Full validation reads
+// the whole upload into memory, it is only bounded by content_length_max.
+func (c Config) validate() error {
+	profile := c.S3.Upload.Profile
+
+	if profile.Avatar.FullValidation && profile.Avatar.ContentLengthMax == 0 {
+		return fmt.Errorf("s3.upload.profile.avatar: full_validation needs content_length_max")
+	}
+	if profile.Banner.FullValidation && profile.Banner.ContentLengthMax == 0 {
+		return fmt.Errorf("s3.upload.profile.banner: full_validation needs content_length_max")
+	}
+
+	return nil
+}
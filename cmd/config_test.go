@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	example, err := os.ReadFile(filepath.Join("..", "config.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name:   "example config",
+			config: string(example),
+		},
+		{
+			name: "full validation without a byte cap",
+			config: strings.Replace(string(example),
+				"content_length_max: 5242880", "content_length_max: 0", 1),
+			wantErr: "s3.upload.profile.avatar: full_validation needs content_length_max",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(tt.config), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			t.Setenv("KV_VIPER_FILE", path)
+
+			cfg, err := LoadConfig()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadConfig() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+
+			// an unmapped key would leave the cap at zero
+			if cfg.S3.Upload.Profile.Avatar.ContentLengthMax != 5<<20 {
+				t.Fatalf("avatar content_length_max = %d, want %d", cfg.S3.Upload.Profile.Avatar.ContentLengthMax, 5<<20)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+-- +migrate Up
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_profiles_username_trgm
+    ON profiles USING GIN (username gin_trgm_ops);
+
+CREATE INDEX idx_profiles_pseudonym_trgm
+    ON profiles USING GIN (pseudonym gin_trgm_ops);
+
+-- expression must match profilesSearchVector in internal/repository/pg/profiles.go
+CREATE INDEX idx_profiles_search_vector
+    ON profiles USING GIN (
+        to_tsvector('simple', coalesce(username, '') || ' ' || coalesce(pseudonym, '') || ' ' || coalesce(description, ''))
+    );
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_profiles_search_vector;
+DROP INDEX IF EXISTS idx_profiles_pseudonym_trgm;
+DROP INDEX IF EXISTS idx_profiles_username_trgm;
@@ -0,0 +1,30 @@
+-- +migrate Up
+CREATE TYPE upload_session_status AS ENUM (
+    'opened',
+    'uploaded',
+    'confirmed',
+    'cancelled',
+    'expired'
+);
+
+CREATE TABLE upload_sessions (
+    id         UUID PRIMARY KEY,
+    account_id UUID NOT NULL REFERENCES profiles (account_id) ON DELETE CASCADE,
+    status     upload_session_status NOT NULL DEFAULT 'opened', -- opened | uploaded | confirmed | cancelled | expired
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_upload_sessions_account_id
+    ON upload_sessions (account_id, created_at);
+
+CREATE INDEX idx_upload_sessions_active_expires_at
+    ON upload_sessions (expires_at)
+    WHERE status IN ('opened', 'uploaded');
+
+-- +migrate Down
+DROP TABLE IF EXISTS upload_sessions CASCADE;
+
+DROP TYPE IF EXISTS upload_session_status;
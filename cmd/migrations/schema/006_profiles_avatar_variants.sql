@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE profiles ADD COLUMN avatar_variants JSONB;
+
+-- +migrate Down
+ALTER TABLE profiles DROP COLUMN IF EXISTS avatar_variants;
@@ -0,0 +1,20 @@
+-- +migrate Up
+CREATE TABLE profile_avatars (
+    id         UUID PRIMARY KEY,
+    account_id UUID NOT NULL REFERENCES profiles (account_id) ON DELETE CASCADE,
+    key        TEXT NOT NULL,
+    variants   JSONB,
+    hash       TEXT NOT NULL,
+    width      INT NOT NULL,
+    height     INT NOT NULL,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    UNIQUE (account_id, hash)
+);
+
+CREATE INDEX idx_profile_avatars_account_id_created_at
+    ON profile_avatars (account_id, created_at DESC);
+
+-- +migrate Down
+DROP TABLE IF EXISTS profile_avatars CASCADE;
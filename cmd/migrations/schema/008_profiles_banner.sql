@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE profiles ADD COLUMN banner TEXT;
+
+-- +migrate Down
+ALTER TABLE profiles DROP COLUMN IF EXISTS banner;
@@ -0,0 +1,8 @@
+-- +migrate Up
+CREATE TABLE profile_tombstones (
+    account_id UUID PRIMARY KEY,
+    deleted_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS profile_tombstones CASCADE;
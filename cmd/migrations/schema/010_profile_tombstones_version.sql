@@ -0,0 +1,7 @@
+-- +migrate Up
+-- the version of the deletion, a profile created again continues above it.
+-- Tombstones written before this migration keep 0, their profiles start over at 1
+ALTER TABLE profile_tombstones ADD COLUMN version BIGINT NOT NULL DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE profile_tombstones DROP COLUMN IF EXISTS version;
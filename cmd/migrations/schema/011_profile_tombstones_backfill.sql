@@ -0,0 +1,20 @@
+-- +migrate Up
+-- Profiles deleted before 009 left no row behind, their tombstones are recovered
+-- from the profile.deleted events still kept in the outbox. Deletions whose events
+-- were already removed can not be recovered, the snapshot publishes no tombstone
+-- for them and consumers have to drop those profiles from the deltas they read.
+WITH deleted AS MATERIALIZED (
+    SELECT key, max(created_at) AS deleted_at
+    FROM outbox_events
+    WHERE type = 'profile.deleted'
+      AND key ~* '^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$'
+    GROUP BY key
+)
+INSERT INTO profile_tombstones (account_id, deleted_at)
+SELECT d.key::uuid, d.deleted_at
+FROM deleted d
+WHERE NOT EXISTS (SELECT 1 FROM profiles p WHERE p.account_id = d.key::uuid)
+ON CONFLICT (account_id) DO NOTHING;
+
+-- +migrate Down
+-- the recovered tombstones can not be told apart from later ones and are kept
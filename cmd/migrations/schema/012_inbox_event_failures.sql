@@ -0,0 +1,10 @@
+-- +migrate Up
+-- why an inbox event was marked failed; evebox owns inbox_events and keeps only the status
+CREATE TABLE inbox_event_failures (
+    event_id  UUID        PRIMARY KEY NOT NULL,
+    reason    TEXT        NOT NULL,
+    failed_at TIMESTAMPTZ NOT NULL DEFAULT (now() AT TIME ZONE 'UTC')
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS inbox_event_failures;
@@ -25,6 +25,9 @@ require (
 	github.com/segmentio/kafka-go v0.4.50
 	github.com/sirupsen/logrus v1.9.3
 	github.com/spf13/viper v1.21.0
+	golang.org/x/image v0.25.0
+	golang.org/x/text v0.29.0
+	google.golang.org/protobuf v1.36.10
 )
 
 require (
@@ -61,5 +64,4 @@ require (
 	go.yaml.in/yaml/v3 v3.0.4 // indirect
 	golang.org/x/sync v0.17.0 // indirect
 	golang.org/x/sys v0.33.0 // indirect
-	golang.org/x/text v0.29.0 // indirect
 )
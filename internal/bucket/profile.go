@@ -1,35 +1,153 @@
 package bucket
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"image"
 	"io"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/netbill/ape"
 	"github.com/netbill/profiles-svc/internal/core/errx"
 	"github.com/netbill/profiles-svc/internal/core/models"
+	"github.com/netbill/profiles-svc/internal/imaging"
+	"github.com/netbill/profiles-svc/internal/moderation"
+	objstorage "github.com/netbill/profiles-svc/internal/storage"
 )
 
-func CreateTempProfileAvatarKey(accountID, sessionID uuid.UUID) string {
-	return fmt.Sprintf("profile/avatar/%s/temp/%s", accountID, sessionID)
+const (
+	tempMediaPrefix       = "profile/tmp/"
+	quarantineMediaPrefix = "quarantine/"
+)
+
+// CreateTempProfileMediaKey is where a session uploads its media, under a prefix of
+// its own so that the janitor lists only temp uploads.
+func CreateTempProfileMediaKey(kind models.ProfileMediaKind, accountID, sessionID uuid.UUID) string {
+	return fmt.Sprintf("%s%s/%s/%s", tempMediaPrefix, kind, accountID, sessionID)
+}
+
+// IsTempProfileMediaKey reports whether key was made by CreateTempProfileMediaKey.
+func IsTempProfileMediaKey(key string) bool {
+	parts := strings.Split(strings.TrimPrefix(key, tempMediaPrefix), "/")
+	return strings.HasPrefix(key, tempMediaPrefix) && len(parts) == 3
+}
+
+// legacyTempProfileMediaPrefix is the prefix of the uploads of a kind in the layout before
+// profile/tmp/, profile/{kind}/{account}/temp/{session}. The sweep lists it for one more
+// release so that uploads left there are removed, drop it afterwards.
+func legacyTempProfileMediaPrefix(kind models.ProfileMediaKind) string {
+	return fmt.Sprintf("profile/%s/", kind)
+}
+
+// isLegacyTempProfileMediaKey reports whether key is a temp upload of the layout before profile/tmp/.
+func isLegacyTempProfileMediaKey(kind models.ProfileMediaKind, key string) bool {
+	prefix := legacyTempProfileMediaPrefix(kind)
+	parts := strings.Split(strings.TrimPrefix(key, prefix), "/")
+	return strings.HasPrefix(key, prefix) && len(parts) == 3 && parts[1] == "temp"
 }
 
-func CreateProfileAvatarKey(accountID uuid.UUID) string {
-	return fmt.Sprintf("profile/avatar/%s", accountID)
+// CreateProfileMediaKey makes a content addressed key, a new upload never
+// overwrites the object a cached URL points to.
+func CreateProfileMediaKey(kind models.ProfileMediaKind, accountID uuid.UUID, hash, format string) string {
+	return fmt.Sprintf("profile/%s/%s/%s.%s", kind, accountID, hash, format)
+}
+
+// CreateQuarantineProfileMediaKey is where an image held by moderation is kept for review,
+// outside of the profile prefix so that it is never served as profile media.
+func CreateQuarantineProfileMediaKey(kind models.ProfileMediaKind, accountID uuid.UUID, hash, format string) string {
+	return quarantineMediaPrefix + CreateProfileMediaKey(kind, accountID, hash, format)
+}
+
+func CreateProfileMediaVariantKey(
+	kind models.ProfileMediaKind,
+	accountID uuid.UUID,
+	hash string,
+	size uint,
+	format string,
+) string {
+	return fmt.Sprintf("profile/%s/%s/%s/%d.%s", kind, accountID, hash, size, format)
+}
+
+// contentHash is the version part of content addressed keys.
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:16])
+}
+
+// ImageVariantName is the name of a variant in the avatar_variants map, e.g. "128_jpeg".
+func ImageVariantName(size uint, format string) string {
+	return fmt.Sprintf("%d_%s", size, format)
+}
+
+// mediaErrors are the errors an upload of one media kind is rejected with.
+type mediaErrors struct {
+	contentFormat *ape.Error
+	contentType   *ape.Error
+	tooLarge      *ape.Error
+	truncated     *ape.Error
+	corrupted     *ape.Error
+	tooManyPixels *ape.Error
+	tooManyFrames *ape.Error
+	aspectRatio   *ape.Error
+	rejected      *ape.Error
+	quarantined   *ape.Error
+}
+
+var profileMediaErrors = map[models.ProfileMediaKind]mediaErrors{
+	models.ProfileMediaKindAvatar: {
+		contentFormat: errx.ErrorProfileAvatarContentFormatIsNotAllowed,
+		contentType:   errx.ErrorProfileAvatarContentTypeIsNotAllowed,
+		tooLarge:      errx.ErrorProfileAvatarTooLarge,
+		truncated:     errx.ErrorProfileAvatarTruncated,
+		corrupted:     errx.ErrorProfileAvatarCorrupted,
+		tooManyPixels: errx.ErrorProfileAvatarTooManyPixels,
+		tooManyFrames: errx.ErrorProfileAvatarTooManyFrames,
+		aspectRatio:   errx.ErrorProfileAvatarAspectRatioIsNotAllowed,
+		rejected:      errx.ErrorProfileAvatarRejected,
+		quarantined:   errx.ErrorProfileAvatarQuarantined,
+	},
+	models.ProfileMediaKindBanner: {
+		contentFormat: errx.ErrorProfileBannerContentFormatIsNotAllowed,
+		contentType:   errx.ErrorProfileBannerContentTypeIsNotAllowed,
+		tooLarge:      errx.ErrorProfileBannerTooLarge,
+		truncated:     errx.ErrorProfileBannerTruncated,
+		corrupted:     errx.ErrorProfileBannerCorrupted,
+		tooManyPixels: errx.ErrorProfileBannerTooManyPixels,
+		tooManyFrames: errx.ErrorProfileBannerTooManyFrames,
+		aspectRatio:   errx.ErrorProfileBannerAspectRatioIsNotAllowed,
+		rejected:      errx.ErrorProfileBannerRejected,
+		quarantined:   errx.ErrorProfileBannerQuarantined,
+	},
+}
+
+func (b Bucket) mediaConfig(kind models.ProfileMediaKind) (MediaConfig, mediaErrors, error) {
+	cfg, ok := b.media[kind]
+	if !ok {
+		return MediaConfig{}, mediaErrors{}, fmt.Errorf("profile media kind %q is not configured", kind)
+	}
+
+	return cfg, profileMediaErrors[kind], nil
 }
 
 func (b Bucket) GetPreloadLinkForProfileMedia(
 	ctx context.Context,
+	kind models.ProfileMediaKind,
 	accountID, sessionID uuid.UUID,
 ) (models.UpdateProfileMediaLinks, error) {
-	uploadURL, getURL, err := b.s3.PresignPut(
+	uploadURL, getURL, err := b.objects.PresignPut(
 		ctx,
-		CreateTempProfileAvatarKey(accountID, sessionID),
-		b.tokensTTL.ProfileAvatar,
+		CreateTempProfileMediaKey(kind, accountID, sessionID),
+		b.tokensTTL.ProfileMedia,
 	)
 	if err != nil {
 		return models.UpdateProfileMediaLinks{}, fmt.Errorf(
-			"failed to presign put object for profile avatar: %w", err,
+			"failed to presign put object for profile %s: %w", kind, err,
 		)
 	}
 
@@ -41,111 +159,370 @@ func (b Bucket) GetPreloadLinkForProfileMedia(
 
 func (b Bucket) AcceptUpdateProfileMedia(
 	ctx context.Context,
+	kind models.ProfileMediaKind,
 	accountID, sessionID uuid.UUID,
-) (string, error) {
-	tempKey := CreateTempProfileAvatarKey(accountID, sessionID)
-	finalKey := CreateProfileAvatarKey(accountID)
+) (models.ProfileMedia, error) {
+	cfg, errs, err := b.mediaConfig(kind)
+	if err != nil {
+		return models.ProfileMedia{}, err
+	}
 
-	rc, size, err := b.s3.GetObjectRange(ctx, tempKey, 2048)
+	tempKey := CreateTempProfileMediaKey(kind, accountID, sessionID)
+
+	rc, size, err := b.objects.GetObjectRange(ctx, tempKey, 2048)
+	if errors.Is(err, objstorage.ErrNotFound) {
+		return models.ProfileMedia{}, errx.ErrorNoContentUploaded.Raise(
+			fmt.Errorf("no content uploaded for profile %s in session %s", kind, sessionID),
+		)
+	}
 	if err != nil {
-		return "", fmt.Errorf("failed to get object range for profile avatar: %w", err)
+		return models.ProfileMedia{}, fmt.Errorf("failed to get object range for profile %s: %w", kind, err)
 	}
 	defer rc.Close()
 
 	if size == 0 {
-		return "", errx.ErrorNoContentUploaded.Raise(
-			fmt.Errorf("no content uploaded for profile avatar in session %s", sessionID),
+		return models.ProfileMedia{}, errx.ErrorNoContentUploaded.Raise(
+			fmt.Errorf("no content uploaded for profile %s in session %s", kind, sessionID),
 		)
 	}
 
 	probe, err := io.ReadAll(rc)
 	if err != nil {
-		return "", fmt.Errorf("failed to read avatar probe bytes: %w", err)
+		return models.ProfileMedia{}, fmt.Errorf("failed to read %s probe bytes: %w", kind, err)
 	}
 
-	valid, err := b.profileAvatarValidator.ValidateImageSize(uint(size))
+	valid, err := cfg.Validator.ValidateImageSize(uint(size))
 	if err != nil {
-		return "", fmt.Errorf("failed to validate profile avatar image size: %w", err)
+		return models.ProfileMedia{}, fmt.Errorf("failed to validate profile %s image size: %w", kind, err)
 	}
 	if !valid {
-		return "", errx.ErrorProfileAvatarTooLarge.Raise(
-			fmt.Errorf("uploaded profile avatar size %d exceeds the maximum allowed size", size),
+		return models.ProfileMedia{}, errs.tooLarge.Raise(
+			fmt.Errorf("uploaded profile %s size %d exceeds the maximum allowed size", kind, size),
 		)
 	}
 
-	valid, err = b.profileAvatarValidator.ValidateImageResolution(probe)
+	valid, err = cfg.Validator.ValidateImageResolution(probe)
 	if err != nil {
-		return "", fmt.Errorf("failed to validate profile avatar image: %w", err)
+		return models.ProfileMedia{}, fmt.Errorf("failed to validate profile %s image: %w", kind, err)
 	}
 	if !valid {
-		return "", errx.ErrorProfileAvatarContentTypeIsNotAllowed.Raise(
+		return models.ProfileMedia{}, errs.contentType.Raise(
 			fmt.Errorf("uploaded file is not a valid image"),
 		)
 	}
 
-	valid, err = b.profileAvatarValidator.ValidateImageFormat(probe)
+	valid, err = cfg.Validator.ValidateImageFormat(probe)
 	if err != nil {
-		return "", fmt.Errorf("failed to validate profile avatar image format: %w", err)
+		return models.ProfileMedia{}, fmt.Errorf("failed to validate profile %s image format: %w", kind, err)
 	}
 	if !valid {
-		return "", errx.ErrorProfileAvatarContentFormatIsNotAllowed.Raise(
-			fmt.Errorf("profile avatar image format is not allowed"),
+		return models.ProfileMedia{}, errs.contentFormat.Raise(
+			fmt.Errorf("profile %s image format is not allowed", kind),
 		)
 	}
 
-	valid, err = b.profileAvatarValidator.ValidateImageContentType(probe)
+	valid, err = cfg.Validator.ValidateImageContentType(probe)
 	if err != nil {
-		return "", fmt.Errorf("failed to validate profile avatar content type: %w", err)
+		return models.ProfileMedia{}, fmt.Errorf("failed to validate profile %s content type: %w", kind, err)
 	}
 	if !valid {
-		return "", errx.ErrorProfileAvatarContentTypeIsNotAllowed.Raise(
-			fmt.Errorf("profile avatar content type is not allowed"),
+		return models.ProfileMedia{}, errs.contentType.Raise(
+			fmt.Errorf("profile %s content type is not allowed", kind),
 		)
 	}
 
-	res, err := b.s3.CopyObject(ctx, tempKey, finalKey)
+	// the probe checks above are only a pre-filter, the whole upload is decoded
+	// and re-encoded so that none of its metadata reaches the final key
+	img, format, err := b.readImage(ctx, tempKey, cfg.Decoding)
+	switch {
+	case errors.Is(err, errTooLarge):
+		return models.ProfileMedia{}, errs.tooLarge.Raise(err)
+	case errors.Is(err, imaging.ErrTruncated):
+		return models.ProfileMedia{}, errs.truncated.Raise(err)
+	case errors.Is(err, imaging.ErrCorrupted):
+		return models.ProfileMedia{}, errs.corrupted.Raise(err)
+	case errors.Is(err, imaging.ErrTooManyPixels):
+		return models.ProfileMedia{}, errs.tooManyPixels.Raise(err)
+	case errors.Is(err, imaging.ErrTooManyFrames):
+		return models.ProfileMedia{}, errs.tooManyFrames.Raise(err)
+	case err != nil:
+		return models.ProfileMedia{}, fmt.Errorf("failed to read uploaded profile %s: %w", kind, err)
+	}
+
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	if !cfg.AspectRatio.Allows(width, height) {
+		return models.ProfileMedia{}, errs.aspectRatio.Raise(
+			fmt.Errorf("profile %s aspect ratio %dx%d is not allowed", kind, width, height),
+		)
+	}
+
+	data, contentType, err := imaging.Encode(img, format)
 	if err != nil {
-		return "", fmt.Errorf("failed to copy object for profile avatar: %w", err)
+		return models.ProfileMedia{}, fmt.Errorf("failed to encode sanitized profile %s: %w", kind, err)
 	}
 
-	return res, nil
+	hash := contentHash(data)
+
+	decision, err := b.moderator.ModerateAvatar(ctx, moderation.Input{
+		AccountID:   accountID,
+		Kind:        string(kind),
+		ContentType: contentType,
+		Data:        data,
+	})
+	if err != nil {
+		return models.ProfileMedia{}, fmt.Errorf("failed to moderate profile %s: %w", kind, err)
+	}
+
+	switch decision.Verdict {
+	case moderation.VerdictReject:
+		return models.ProfileMedia{}, errs.rejected.Raise(
+			fmt.Errorf("profile %s was rejected by moderation: %s", kind, decision.Reason),
+		)
+	case moderation.VerdictQuarantine:
+		quarantineKey := CreateQuarantineProfileMediaKey(kind, accountID, hash, format)
+		err = b.objects.PutObject(ctx, quarantineKey, bytes.NewReader(data), int64(len(data)), contentType)
+		if err != nil {
+			return models.ProfileMedia{}, fmt.Errorf("failed to put quarantined profile %s: %w", kind, err)
+		}
+
+		return models.ProfileMedia{}, errs.quarantined.Raise(
+			fmt.Errorf("profile %s is held for review under %s: %s", kind, quarantineKey, decision.Reason),
+		)
+	}
+
+	finalKey := CreateProfileMediaKey(kind, accountID, hash, format)
+
+	err = b.objects.PutObject(ctx, finalKey, bytes.NewReader(data), int64(len(data)), contentType)
+	if err != nil {
+		return models.ProfileMedia{}, fmt.Errorf("failed to put sanitized profile %s: %w", kind, err)
+	}
+
+	variants, err := b.createImageVariants(ctx, img, cfg.Variants,
+		func(size uint, format string) string {
+			return CreateProfileMediaVariantKey(kind, accountID, hash, size, format)
+		},
+	)
+	if err != nil {
+		return models.ProfileMedia{}, fmt.Errorf("failed to create profile %s variants: %w", kind, err)
+	}
+
+	return models.ProfileMedia{
+		Kind:     kind,
+		Key:      finalKey,
+		Variants: variants,
+		Hash:     hash,
+		Width:    width,
+		Height:   height,
+	}, nil
+}
+
+var errTooLarge = errors.New("object is too large")
+
+// defaultImageMaxBytes and defaultImageMaxPixels bound the decoding of a media kind
+// that configures no limit, an upload is never read or decoded without one.
+const (
+	defaultImageMaxBytes  = 20 << 20
+	defaultImageMaxPixels = 40_000_000
+)
+
+// readImage reads and decodes the image stored under key with its EXIF orientation applied.
+// The byte limit and the pixel cap apply in both modes, the full mode adds the structure checks.
+func (b Bucket) readImage(ctx context.Context, key string, cfg ImageDecoding) (image.Image, string, error) {
+	maxBytes := cfg.MaxBytes
+	if maxBytes == 0 {
+		maxBytes = defaultImageMaxBytes
+	}
+	limits := cfg.Limits
+	if limits.MaxPixels == 0 {
+		limits.MaxPixels = defaultImageMaxPixels
+	}
+
+	rc, size, err := b.objects.GetObject(ctx, key)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get object %s: %w", key, err)
+	}
+	defer rc.Close()
+
+	if size > int64(maxBytes) {
+		return nil, "", fmt.Errorf("%w: object %s is %d bytes, max %d", errTooLarge, key, size, maxBytes)
+	}
+
+	// the object may have been replaced since its size was checked
+	data, err := io.ReadAll(io.LimitReader(rc, int64(maxBytes)+1))
+	switch {
+	case err != nil:
+		return nil, "", fmt.Errorf("failed to read object %s: %w", key, err)
+	case uint(len(data)) > maxBytes:
+		return nil, "", fmt.Errorf("%w: object %s exceeds %d bytes", errTooLarge, key, maxBytes)
+	}
+
+	if !cfg.Full {
+		img, format, err := imaging.DecodeBounded(data, limits.MaxPixels)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to decode image %s: %w", key, err)
+		}
+
+		return img, format, nil
+	}
+
+	if int64(len(data)) < size {
+		return nil, "", fmt.Errorf("%w: object %s has %d of %d bytes", imaging.ErrTruncated, key, len(data), size)
+	}
+
+	img, format, err := imaging.DecodeStrict(data, limits)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode image %s: %w", key, err)
+	}
+
+	return img, format, nil
+}
+
+func (b Bucket) putImage(ctx context.Context, key string, img image.Image, format string) error {
+	data, contentType, err := imaging.Encode(img, format)
+	if err != nil {
+		return err
+	}
+
+	err = b.objects.PutObject(ctx, key, bytes.NewReader(data), int64(len(data)), contentType)
+	if err != nil {
+		return fmt.Errorf("failed to put image %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// createImageVariants stores the variants of img under the keys made by variantKey,
+// returns the variant keys by variant name.
+func (b Bucket) createImageVariants(
+	ctx context.Context,
+	img image.Image,
+	cfg ImageVariants,
+	variantKey func(size uint, format string) string,
+) (map[string]string, error) {
+	if len(cfg.Sizes) == 0 || len(cfg.Formats) == 0 {
+		return nil, nil
+	}
+
+	variants := make(map[string]string, len(cfg.Sizes)*len(cfg.Formats))
+	for _, size := range cfg.Sizes {
+		resized := imaging.Square(img, int(size))
+
+		for _, format := range cfg.Formats {
+			vkey := variantKey(size, format)
+			if err := b.putImage(ctx, vkey, resized, format); err != nil {
+				return nil, err
+			}
+
+			variants[ImageVariantName(size, format)] = vkey
+		}
+	}
+
+	return variants, nil
 }
 
+// CleanProfileMediaSession deletes the temp objects of every media kind of the session.
 func (b Bucket) CleanProfileMediaSession(
 	ctx context.Context,
 	accountID, sessionID uuid.UUID,
 ) error {
-	err := b.s3.DeleteObject(ctx, CreateTempProfileAvatarKey(accountID, sessionID))
-	if err != nil {
-		return fmt.Errorf(
-			"failed to delete temp object for profile avatar: %w", err,
-		)
+	for _, kind := range models.ProfileMediaKinds {
+		err := b.objects.DeleteObject(ctx, CreateTempProfileMediaKey(kind, accountID, sessionID))
+		if err != nil {
+			return fmt.Errorf(
+				"failed to delete temp object for profile %s: %w", kind, err,
+			)
+		}
 	}
 
 	return nil
 }
 
-func (b Bucket) CancelUpdateProfileAvatar(
+func (b Bucket) CancelUpdateProfileMedia(
 	ctx context.Context,
+	kind models.ProfileMediaKind,
 	accountID, sessionID uuid.UUID,
 ) error {
-	err := b.s3.DeleteObject(ctx, CreateTempProfileAvatarKey(accountID, sessionID))
+	err := b.objects.DeleteObject(ctx, CreateTempProfileMediaKey(kind, accountID, sessionID))
 	if err != nil {
 		return fmt.Errorf(
-			"failed to delete temp object for profile avatar: %w", err,
+			"failed to delete temp object for profile %s: %w", kind, err,
 		)
 	}
 
 	return nil
 }
 
-func (b Bucket) DeleteProfileAvatar(ctx context.Context, accountID uuid.UUID) error {
-	err := b.s3.DeleteObject(ctx, CreateProfileAvatarKey(accountID))
+// DeleteProfileMedia deletes one stored media version together with its variants.
+func (b Bucket) DeleteProfileMedia(ctx context.Context, key string, variants map[string]string) error {
+	err := b.objects.DeleteObject(ctx, key)
 	if err != nil {
 		return fmt.Errorf(
-			"failed to delete object for profile avatar: %w", err,
+			"failed to delete object %s for profile media: %w", key, err,
 		)
 	}
 
+	for _, vkey := range variants {
+		if err = b.objects.DeleteObject(ctx, vkey); err != nil {
+			return fmt.Errorf(
+				"failed to delete object %s for profile media variant: %w", vkey, err,
+			)
+		}
+	}
+
 	return nil
 }
+
+// DeleteAbandonedProfileMediaUploads deletes temp media objects last modified before the given time,
+// those of the previous layout included. A failed delete does not stop the sweep, it is counted
+// and reported in the returned error.
+func (b Bucket) DeleteAbandonedProfileMediaUploads(
+	ctx context.Context,
+	before time.Time,
+) (models.TempMediaSweep, error) {
+	var (
+		res     models.TempMediaSweep
+		lastErr error
+	)
+	sweep := func(prefix string, isTemp func(key string) bool) error {
+		return b.objects.ListObjects(ctx, prefix, func(objects []objstorage.Object) error {
+			for _, obj := range objects {
+				if !isTemp(obj.Key) {
+					continue
+				}
+
+				res.Scanned++
+				if !obj.LastModified.Before(before) {
+					continue
+				}
+
+				if err := b.objects.DeleteObject(ctx, obj.Key); err != nil {
+					res.Failed++
+					lastErr = fmt.Errorf("failed to delete temp object %s for profile media: %w", obj.Key, err)
+					continue
+				}
+				res.Deleted++
+			}
+
+			return nil
+		})
+	}
+
+	if err := sweep(tempMediaPrefix, IsTempProfileMediaKey); err != nil {
+		return res, fmt.Errorf("failed to list temp profile media objects: %w", err)
+	}
+
+	for _, kind := range models.ProfileMediaKinds {
+		err := sweep(legacyTempProfileMediaPrefix(kind), func(key string) bool {
+			return isLegacyTempProfileMediaKey(kind, key)
+		})
+		if err != nil {
+			return res, fmt.Errorf("failed to list legacy temp profile %s objects: %w", kind, err)
+		}
+	}
+
+	if lastErr != nil {
+		return res, fmt.Errorf("%d temp profile media objects not deleted, last error: %w", res.Failed, lastErr)
+	}
+
+	return res, nil
+}
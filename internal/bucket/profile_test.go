@@ -0,0 +1,234 @@
+package bucket
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/netbill/profiles-svc/internal/core/models"
+	"github.com/netbill/profiles-svc/internal/imaging"
+	objstorage "github.com/netbill/profiles-svc/internal/storage"
+)
+
+func TestIsTempProfileMediaKey(t *testing.T) {
+	accountID := uuid.New()
+	sessionID := uuid.New()
+
+	tests := []struct {
+		name string
+		key  string
+		want bool
+	}{
+		{
+			name: "temp avatar",
+			key:  CreateTempProfileMediaKey(models.ProfileMediaKindAvatar, accountID, sessionID),
+			want: true,
+		},
+		{
+			name: "temp banner",
+			key:  CreateTempProfileMediaKey(models.ProfileMediaKindBanner, accountID, sessionID),
+			want: true,
+		},
+		{
+			name: "stored media",
+			key:  CreateProfileMediaKey(models.ProfileMediaKindAvatar, accountID, "abc", "png"),
+			want: false,
+		},
+		{
+			name: "variant",
+			key:  CreateProfileMediaVariantKey(models.ProfileMediaKindAvatar, accountID, "abc", 128, "jpeg"),
+			want: false,
+		},
+		{
+			name: "quarantined media",
+			key:  CreateQuarantineProfileMediaKey(models.ProfileMediaKindAvatar, accountID, "abc", "png"),
+			want: false,
+		},
+		{
+			name: "temp prefix only",
+			key:  tempMediaPrefix,
+			want: false,
+		},
+		{
+			name: "nested under temp session",
+			key:  CreateTempProfileMediaKey(models.ProfileMediaKindAvatar, accountID, sessionID) + "/x",
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTempProfileMediaKey(tt.key); got != tt.want {
+				t.Errorf("IsTempProfileMediaKey(%q) = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+// objectStorage serves one object, size is what the storage reports for it.
+type objectStorage struct {
+	Storage
+
+	data []byte
+	size int64
+}
+
+func (s objectStorage) GetObject(context.Context, string) (io.ReadCloser, int64, error) {
+	return io.NopCloser(bytes.NewReader(s.data)), s.size, nil
+}
+
+func TestReadImageLimits(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 100, 100))); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	size := int64(len(data))
+
+	tests := []struct {
+		name     string
+		size     int64
+		decoding ImageDecoding
+		wantErr  error
+	}{
+		{
+			name:     "probe mode within the limits",
+			size:     size,
+			decoding: ImageDecoding{MaxBytes: uint(size), Limits: imaging.Limits{MaxPixels: 100 * 100}},
+		},
+		{
+			name:     "probe mode over the byte limit",
+			size:     size,
+			decoding: ImageDecoding{MaxBytes: uint(size) - 1},
+			wantErr:  errTooLarge,
+		},
+		{
+			name:     "probe mode object grew after the size check",
+			size:     1,
+			decoding: ImageDecoding{MaxBytes: uint(size) - 1},
+			wantErr:  errTooLarge,
+		},
+		{
+			name:     "probe mode over the pixel cap",
+			size:     size,
+			decoding: ImageDecoding{Limits: imaging.Limits{MaxPixels: 100*100 - 1}},
+			wantErr:  imaging.ErrTooManyPixels,
+		},
+		{
+			name: "no limits configured use the defaults",
+			size: defaultImageMaxBytes + 1,
+			// the reported size alone is over the default byte limit
+			decoding: ImageDecoding{},
+			wantErr:  errTooLarge,
+		},
+		{
+			name:     "full mode over the pixel cap",
+			size:     size,
+			decoding: ImageDecoding{Full: true, Limits: imaging.Limits{MaxPixels: 100}},
+			wantErr:  imaging.ErrTooManyPixels,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := Bucket{objects: objectStorage{data: data, size: tt.size}}
+
+			_, _, err := b.readImage(context.Background(), "key", tt.decoding)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("readImage() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestImageVariantsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		formats []string
+		wantErr bool
+	}{
+		{name: "jpeg and png", formats: []string{"jpeg", "png"}},
+		{name: "webp", formats: []string{"webp"}, wantErr: true},
+		{name: "avif", formats: []string{"jpeg", "avif"}, wantErr: true},
+		{name: "gif", formats: []string{"gif"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ImageVariants{Sizes: []uint{64}, Formats: tt.formats}.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// listStorage lists its objects by prefix and records the deleted keys.
+type listStorage struct {
+	Storage
+
+	objects []objstorage.Object
+	deleted []string
+}
+
+func (s *listStorage) ListObjects(_ context.Context, prefix string, fn func(objects []objstorage.Object) error) error {
+	var page []objstorage.Object
+	for _, obj := range s.objects {
+		if strings.HasPrefix(obj.Key, prefix) {
+			page = append(page, obj)
+		}
+	}
+
+	return fn(page)
+}
+
+func (s *listStorage) DeleteObject(_ context.Context, key string) error {
+	s.deleted = append(s.deleted, key)
+	return nil
+}
+
+func TestDeleteAbandonedProfileMediaUploads(t *testing.T) {
+	accountID := uuid.New()
+	sessionID := uuid.New()
+	now := time.Now().UTC()
+	old := now.Add(-2 * time.Hour)
+
+	legacyAvatar := fmt.Sprintf("profile/avatar/%s/temp/%s", accountID, sessionID)
+	legacyBanner := fmt.Sprintf("profile/banner/%s/temp/%s", accountID, sessionID)
+	recentLegacy := fmt.Sprintf("profile/avatar/%s/temp/%s", accountID, uuid.New())
+	temp := CreateTempProfileMediaKey(models.ProfileMediaKindAvatar, accountID, sessionID)
+
+	s := &listStorage{objects: []objstorage.Object{
+		{Key: temp, LastModified: old},
+		{Key: legacyAvatar, LastModified: old},
+		{Key: legacyBanner, LastModified: old},
+		{Key: recentLegacy, LastModified: now},
+		{Key: CreateProfileMediaKey(models.ProfileMediaKindAvatar, accountID, "abc", "png"), LastModified: old},
+		{Key: CreateProfileMediaVariantKey(models.ProfileMediaKindAvatar, accountID, "abc", 128, "jpeg"), LastModified: old},
+	}}
+	b := Bucket{objects: s}
+
+	res, err := b.DeleteAbandonedProfileMediaUploads(context.Background(), now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("DeleteAbandonedProfileMediaUploads() error = %v", err)
+	}
+
+	want := []string{temp, legacyAvatar, legacyBanner}
+	sort.Strings(want)
+	sort.Strings(s.deleted)
+	if fmt.Sprint(s.deleted) != fmt.Sprint(want) {
+		t.Fatalf("deleted = %v, want %v", s.deleted, want)
+	}
+	if res != (models.TempMediaSweep{Scanned: 4, Deleted: 3}) {
+		t.Fatalf("sweep = %+v, want 4 scanned and 3 deleted", res)
+	}
+}
@@ -2,38 +2,133 @@ package bucket
 
 import (
 	"context"
+	"fmt"
 	_ "image/gif"
 	_ "image/jpeg"
 	_ "image/png"
 	"io"
+	"slices"
 	"time"
+
+	"github.com/netbill/profiles-svc/internal/core/models"
+	"github.com/netbill/profiles-svc/internal/imaging"
+	"github.com/netbill/profiles-svc/internal/moderation"
+	objstorage "github.com/netbill/profiles-svc/internal/storage"
 )
 
 type Bucket struct {
-	s3                     storage
-	profileAvatarValidator ObjectValidator
-	tokensTTL              UploadTokensTTL
+	objects   Storage
+	media     map[models.ProfileMediaKind]MediaConfig
+	tokensTTL UploadTokensTTL
+	moderator AvatarModerator
 }
 
 type UploadTokensTTL struct {
-	ProfileAvatar time.Duration
+	ProfileMedia time.Duration
+}
+
+// MediaConfig are the upload rules of one profile media kind.
+type MediaConfig struct {
+	Validator   ObjectValidator
+	AspectRatio AspectRatio
+	// Variants are square crops, leave them empty for kinds that are not square.
+	Variants ImageVariants
+	Decoding ImageDecoding
+}
+
+// AspectRatio bounds width / height of an accepted image, a zero bound is not checked.
+type AspectRatio struct {
+	Min float64
+	Max float64
+}
+
+func (r AspectRatio) Allows(width, height int) bool {
+	if height == 0 {
+		return false
+	}
+
+	ratio := float64(width) / float64(height)
+	return (r.Min == 0 || ratio >= r.Min) && (r.Max == 0 || ratio <= r.Max)
+}
+
+func (r AspectRatio) Validate() error {
+	if r.Min < 0 || r.Max < 0 {
+		return fmt.Errorf("aspect ratio bounds must not be negative")
+	}
+	if r.Max > 0 && r.Min > r.Max {
+		return fmt.Errorf("aspect ratio min %g is greater than max %g", r.Min, r.Max)
+	}
+
+	return nil
+}
+
+// variantFormats are the formats variants are encoded to, there is no vetted
+// pure Go encoder for WebP or AVIF.
+var variantFormats = []string{"jpeg", "png"}
+
+// ImageVariants are the derivatives made from an accepted image,
+// one square image per size and format.
+type ImageVariants struct {
+	Sizes   []uint
+	Formats []string
+}
+
+func (v ImageVariants) Validate() error {
+	for _, size := range v.Sizes {
+		if size == 0 {
+			return fmt.Errorf("image variant size must be positive")
+		}
+	}
+	for _, format := range v.Formats {
+		if !slices.Contains(variantFormats, format) {
+			return fmt.Errorf("image variant format %q is not supported, use one of %v", format, variantFormats)
+		}
+	}
+
+	return nil
+}
+
+// ImageDecoding controls how an accepted upload is read and decoded. The upload is
+// never read past MaxBytes nor decoded above Limits.MaxPixels, zero ones fall back
+// to the defaults of the bucket.
+type ImageDecoding struct {
+	// Full enables the full validation mode: the whole structure is checked against
+	// Limits before decoding and a truncated or corrupted image is rejected.
+	// Otherwise only the probe checks and the size caps are applied.
+	Full     bool
+	MaxBytes uint
+	Limits   imaging.Limits
 }
 
 type Config struct {
-	S3                     storage
-	ProfileAvatarValidator ObjectValidator
-	UploadTokensTTL        UploadTokensTTL
+	Storage         Storage
+	ProfileAvatar   MediaConfig
+	ProfileBanner   MediaConfig
+	UploadTokensTTL UploadTokensTTL
+	// Moderator reviews every accepted image before it is stored,
+	// nil allows every image.
+	Moderator AvatarModerator
 }
 
 func New(config Config) Bucket {
+	var moderator AvatarModerator = moderation.Noop{}
+	if config.Moderator != nil {
+		moderator = config.Moderator
+	}
+
 	return Bucket{
-		s3:                     config.S3,
-		tokensTTL:              config.UploadTokensTTL,
-		profileAvatarValidator: config.ProfileAvatarValidator,
+		objects:   config.Storage,
+		tokensTTL: config.UploadTokensTTL,
+		moderator: moderator,
+		media: map[models.ProfileMediaKind]MediaConfig{
+			models.ProfileMediaKindAvatar: config.ProfileAvatar,
+			models.ProfileMediaKindBanner: config.ProfileBanner,
+		},
 	}
 }
 
-type storage interface {
+// Storage is the object storage the bucket keeps media in, S3 or the local filesystem.
+type Storage interface {
 	PresignPut(
 		ctx context.Context,
 		key string,
@@ -45,8 +140,19 @@ type storage interface {
 		key string,
 		bytes int64,
 	) (body io.ReadCloser, size int64, err error)
+	GetObject(ctx context.Context, key string) (body io.ReadCloser, size int64, err error)
+	PutObject(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
 	CopyObject(ctx context.Context, tmplKey, finalKey string) (string, error)
 	DeleteObject(ctx context.Context, key string) error
+
+	// ListObjects calls fn with every page of objects whose key starts with prefix.
+	ListObjects(ctx context.Context, prefix string, fn func(objects []objstorage.Object) error) error
+}
+
+// AvatarModerator decides whether an uploaded image may go live, it gets the
+// sanitized bytes so that it reviews exactly what would be served.
+type AvatarModerator interface {
+	ModerateAvatar(ctx context.Context, input moderation.Input) (moderation.Decision, error)
 }
 
 type ObjectValidator interface {
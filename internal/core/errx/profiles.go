@@ -4,4 +4,14 @@ import (
 	"github.com/netbill/ape"
 )
 
-var ErrorProfileNotFound = ape.DeclareError("PROFILE_NOT_FOUND")
+var (
+	ErrorProfileNotFound        = ape.DeclareError("PROFILE_NOT_FOUND")
+	ErrorProfileVersionMismatch = ape.DeclareError("PROFILE_VERSION_MISMATCH")
+
+	ErrorProfilePseudonymTooLong      = ape.DeclareError("PROFILE_PSEUDONYM_TOO_LONG")
+	ErrorProfilePseudonymNotAllowed   = ape.DeclareError("PROFILE_PSEUDONYM_NOT_ALLOWED")
+	ErrorProfileDescriptionTooLong    = ape.DeclareError("PROFILE_DESCRIPTION_TOO_LONG")
+	ErrorProfileDescriptionNotAllowed = ape.DeclareError("PROFILE_DESCRIPTION_NOT_ALLOWED")
+
+	ErrorProfileAvatarNotFound = ape.DeclareError("PROFILE_AVATAR_NOT_FOUND")
+)
@@ -6,6 +6,24 @@ var (
 	ErrorProfileAvatarContentFormatIsNotAllowed = ape.DeclareError("PROFILE_AVATAR_CONTENT_FORMAT_IS_NOT_ALLOWED")
 	ErrorProfileAvatarContentTypeIsNotAllowed   = ape.DeclareError("PROFILE_AVATAR_CONTENT_TYPE_IS_NOT_ALLOWED")
 	ErrorProfileAvatarTooLarge                  = ape.DeclareError("PROFILE_AVATAR_TOO_LARGE")
+	ErrorProfileAvatarTruncated                 = ape.DeclareError("PROFILE_AVATAR_TRUNCATED")
+	ErrorProfileAvatarCorrupted                 = ape.DeclareError("PROFILE_AVATAR_CORRUPTED")
+	ErrorProfileAvatarTooManyPixels             = ape.DeclareError("PROFILE_AVATAR_TOO_MANY_PIXELS")
+	ErrorProfileAvatarTooManyFrames             = ape.DeclareError("PROFILE_AVATAR_TOO_MANY_FRAMES")
+	ErrorProfileAvatarAspectRatioIsNotAllowed   = ape.DeclareError("PROFILE_AVATAR_ASPECT_RATIO_IS_NOT_ALLOWED")
+	ErrorProfileAvatarRejected                  = ape.DeclareError("PROFILE_AVATAR_REJECTED")
+	ErrorProfileAvatarQuarantined               = ape.DeclareError("PROFILE_AVATAR_QUARANTINED")
+
+	ErrorProfileBannerContentFormatIsNotAllowed = ape.DeclareError("PROFILE_BANNER_CONTENT_FORMAT_IS_NOT_ALLOWED")
+	ErrorProfileBannerContentTypeIsNotAllowed   = ape.DeclareError("PROFILE_BANNER_CONTENT_TYPE_IS_NOT_ALLOWED")
+	ErrorProfileBannerTooLarge                  = ape.DeclareError("PROFILE_BANNER_TOO_LARGE")
+	ErrorProfileBannerTruncated                 = ape.DeclareError("PROFILE_BANNER_TRUNCATED")
+	ErrorProfileBannerCorrupted                 = ape.DeclareError("PROFILE_BANNER_CORRUPTED")
+	ErrorProfileBannerTooManyPixels             = ape.DeclareError("PROFILE_BANNER_TOO_MANY_PIXELS")
+	ErrorProfileBannerTooManyFrames             = ape.DeclareError("PROFILE_BANNER_TOO_MANY_FRAMES")
+	ErrorProfileBannerAspectRatioIsNotAllowed   = ape.DeclareError("PROFILE_BANNER_ASPECT_RATIO_IS_NOT_ALLOWED")
+	ErrorProfileBannerRejected                  = ape.DeclareError("PROFILE_BANNER_REJECTED")
+	ErrorProfileBannerQuarantined               = ape.DeclareError("PROFILE_BANNER_QUARANTINED")
 
 	ErrorNoContentUploaded = ape.DeclareError("NO_CONTENT_UPLOADED")
 )
@@ -0,0 +1,9 @@
+package errx
+
+import "github.com/netbill/ape"
+
+var (
+	ErrorUploadSessionNotFound = ape.DeclareError("UPLOAD_SESSION_NOT_FOUND")
+	ErrorUploadSessionClosed   = ape.DeclareError("UPLOAD_SESSION_CLOSED")
+	ErrorUploadSessionExpired  = ape.DeclareError("UPLOAD_SESSION_EXPIRED")
+)
@@ -14,6 +14,14 @@ type Profile struct {
 	Description *string   `json:"description,omitempty"`
 	Avatar      *string   `json:"avatar,omitempty"`
 
+	// AvatarVariants are the resized copies of Avatar by variant name, e.g. "128_jpeg".
+	AvatarVariants map[string]string `json:"avatar_variants,omitempty"`
+
+	Banner *string `json:"banner,omitempty"`
+
+	// Version is incremented on every update, used for optimistic concurrency.
+	Version int64 `json:"version"`
+
 	UpdatedAt time.Time `json:"updated_at"`
 	CreatedAt time.Time `json:"created_at"`
 }
@@ -22,13 +30,38 @@ func (e Profile) IsNil() bool {
 	return e.AccountID == uuid.Nil
 }
 
+// ProfileMediaKind names a media slot of the profile, every kind is uploaded
+// in the update session under its own keys and validated by its own rules.
+type ProfileMediaKind string
+
+const (
+	ProfileMediaKindAvatar ProfileMediaKind = "avatar"
+	ProfileMediaKindBanner ProfileMediaKind = "banner"
+)
+
+// ProfileMediaKinds are all media slots of the profile.
+var ProfileMediaKinds = []ProfileMediaKind{
+	ProfileMediaKindAvatar,
+	ProfileMediaKindBanner,
+}
+
+// ProfileMedia is an accepted upload stored under its final key.
+type ProfileMedia struct {
+	Kind     ProfileMediaKind  `json:"kind"`
+	Key      string            `json:"key"`
+	Variants map[string]string `json:"variants,omitempty"`
+	Hash     string            `json:"hash"`
+	Width    int               `json:"width"`
+	Height   int               `json:"height"`
+}
+
 type UpdateProfileMediaLinks struct {
 	UploadURL string `json:"upload_url"`
 	GetURL    string `json:"get_url"`
 }
 
 type UpdateProfileMedia struct {
-	Links           UpdateProfileMediaLinks `json:"links"`
-	UploadSessionID uuid.UUID               `json:"upload_session_id"`
-	UploadToken     string                  `json:"upload_token"`
+	Links           map[ProfileMediaKind]UpdateProfileMediaLinks `json:"links"`
+	UploadSessionID uuid.UUID                                    `json:"upload_session_id"`
+	UploadToken     string                                       `json:"upload_token"`
 }
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProfileAvatar is an accepted avatar version kept in the account's avatar history.
+type ProfileAvatar struct {
+	ID        uuid.UUID         `json:"id"`
+	AccountID uuid.UUID         `json:"account_id"`
+	Key       string            `json:"key"`
+	Variants  map[string]string `json:"variants,omitempty"`
+	Hash      string            `json:"hash"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+func (a ProfileAvatar) IsNil() bool {
+	return a.ID == uuid.Nil
+}
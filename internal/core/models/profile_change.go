@@ -0,0 +1,102 @@
+package models
+
+// ProfileField names a profile field in a change set.
+type ProfileField string
+
+const (
+	ProfileFieldUsername    ProfileField = "username"
+	ProfileFieldOfficial    ProfileField = "official"
+	ProfileFieldPseudonym   ProfileField = "pseudonym"
+	ProfileFieldDescription ProfileField = "description"
+	ProfileFieldAvatar      ProfileField = "avatar"
+	ProfileFieldBanner      ProfileField = "banner"
+)
+
+// Media reports whether the field holds a stored media key. The keys are
+// not part of a change, a media change carries only the field.
+func (f ProfileField) Media() bool {
+	return f == ProfileFieldAvatar || f == ProfileFieldBanner
+}
+
+// ProfileFieldChange is the change of one field, a nil value is an unset field.
+// The avatar variants follow the avatar, they are not reported on their own.
+type ProfileFieldChange struct {
+	Field ProfileField `json:"field"`
+	Old   any          `json:"old"`
+	New   any          `json:"new"`
+}
+
+type ProfileChanges []ProfileFieldChange
+
+// Get returns the change of the field, ok is false when the field did not change.
+func (c ProfileChanges) Get(field ProfileField) (change ProfileFieldChange, ok bool) {
+	for _, ch := range c {
+		if ch.Field == field {
+			return ch, true
+		}
+	}
+
+	return ProfileFieldChange{}, false
+}
+
+// DiffProfiles returns the fields that differ between the profile before and after an update.
+func DiffProfiles(before, after Profile) ProfileChanges {
+	changes := make(ProfileChanges, 0)
+
+	if before.Username != after.Username {
+		changes = append(changes, ProfileFieldChange{
+			Field: ProfileFieldUsername,
+			Old:   before.Username,
+			New:   after.Username,
+		})
+	}
+	if before.Official != after.Official {
+		changes = append(changes, ProfileFieldChange{
+			Field: ProfileFieldOfficial,
+			Old:   before.Official,
+			New:   after.Official,
+		})
+	}
+
+	optional := []struct {
+		field         ProfileField
+		before, after *string
+	}{
+		{ProfileFieldPseudonym, before.Pseudonym, after.Pseudonym},
+		{ProfileFieldDescription, before.Description, after.Description},
+		{ProfileFieldAvatar, before.Avatar, after.Avatar},
+		{ProfileFieldBanner, before.Banner, after.Banner},
+	}
+	for _, f := range optional {
+		if equalOptional(f.before, f.after) {
+			continue
+		}
+
+		change := ProfileFieldChange{Field: f.field}
+		if !f.field.Media() {
+			change.Old = optionalValue(f.before)
+			change.New = optionalValue(f.after)
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+func equalOptional(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+// optionalValue keeps an unset field a plain nil, not a nil *string in an interface.
+func optionalValue(v *string) any {
+	if v == nil {
+		return nil
+	}
+
+	return *v
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func ptr(s string) *string {
+	return &s
+}
+
+func TestDiffProfiles(t *testing.T) {
+	base := Profile{
+		Username:    "alice",
+		Pseudonym:   ptr("Alice"),
+		Description: ptr("hi"),
+		Avatar:      ptr("profile/avatar/a/1"),
+	}
+
+	tests := []struct {
+		name   string
+		update func(p *Profile)
+		want   ProfileChanges
+	}{
+		{
+			name:   "nothing changed",
+			update: func(p *Profile) {},
+			want:   ProfileChanges{},
+		},
+		{
+			name:   "username and official",
+			update: func(p *Profile) { p.Username = "bob"; p.Official = true },
+			want: ProfileChanges{
+				{Field: ProfileFieldUsername, Old: "alice", New: "bob"},
+				{Field: ProfileFieldOfficial, Old: false, New: true},
+			},
+		},
+		{
+			name:   "pseudonym unset",
+			update: func(p *Profile) { p.Pseudonym = nil },
+			want:   ProfileChanges{{Field: ProfileFieldPseudonym, Old: "Alice", New: nil}},
+		},
+		{
+			name:   "same description in a new pointer",
+			update: func(p *Profile) { p.Description = ptr("hi") },
+			want:   ProfileChanges{},
+		},
+		{
+			name:   "avatar replaced without keys",
+			update: func(p *Profile) { p.Avatar = ptr("profile/avatar/a/2") },
+			want:   ProfileChanges{{Field: ProfileFieldAvatar}},
+		},
+		{
+			name:   "banner set without keys",
+			update: func(p *Profile) { p.Banner = ptr("profile/banner/a/1") },
+			want:   ProfileChanges{{Field: ProfileFieldBanner}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			after := base
+			tt.update(&after)
+
+			got := DiffProfiles(base, after)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("DiffProfiles() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
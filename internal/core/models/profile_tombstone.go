@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProfileTombstone records a deleted profile, the snapshot publishes it
+// as a deleted marker that replaces the state of the profile downstream.
+// Version is the version of the deletion, one above the last version of the
+// profile, a profile created again for the account continues above it.
+type ProfileTombstone struct {
+	AccountID uuid.UUID `json:"account_id"`
+	Version   int64     `json:"version"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
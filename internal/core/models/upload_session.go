@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	UploadSessionStatusOpened    = "opened"
+	UploadSessionStatusUploaded  = "uploaded"
+	UploadSessionStatusConfirmed = "confirmed"
+	UploadSessionStatusCancelled = "cancelled"
+	UploadSessionStatusExpired   = "expired"
+)
+
+// UploadSessionActiveStatuses are the statuses a session can still be confirmed or cancelled from.
+var UploadSessionActiveStatuses = []string{
+	UploadSessionStatusOpened,
+	UploadSessionStatusUploaded,
+}
+
+type UploadSession struct {
+	ID        uuid.UUID `json:"id"`
+	AccountID uuid.UUID `json:"account_id"`
+	Status    string    `json:"status"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (s UploadSession) IsNil() bool {
+	return s.ID == uuid.Nil
+}
+
+func (s UploadSession) IsActive() bool {
+	return s.Status == UploadSessionStatusOpened || s.Status == UploadSessionStatusUploaded
+}
+
+// TempMediaSweep reports a sweep of abandoned temporary uploads.
+type TempMediaSweep struct {
+	Scanned int `json:"scanned"`
+	Deleted int `json:"deleted"`
+	Failed  int `json:"failed"`
+}
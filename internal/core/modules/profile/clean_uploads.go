@@ -0,0 +1,35 @@
+package profile
+
+import (
+	"context"
+	"time"
+
+	"github.com/netbill/profiles-svc/internal/core/models"
+)
+
+type CleanUploadsReport struct {
+	ExpiredSessions int64
+	TempMedia       models.TempMediaSweep
+}
+
+// CleanAbandonedUploads expires upload sessions past their deadline and deletes
+// temp uploads older than olderThan. olderThan should be longer than the upload
+// session TTL, otherwise uploads of still open sessions are removed.
+func (m *Module) CleanAbandonedUploads(ctx context.Context, olderThan time.Duration) (CleanUploadsReport, error) {
+	now := time.Now().UTC()
+
+	expired, err := m.repo.ExpireUploadSessions(ctx, now)
+	if err != nil {
+		return CleanUploadsReport{}, err
+	}
+
+	sweep, err := m.bucket.DeleteAbandonedProfileMediaUploads(ctx, now.Add(-olderThan))
+	if err != nil {
+		return CleanUploadsReport{ExpiredSessions: expired, TempMedia: sweep}, err
+	}
+
+	return CleanUploadsReport{
+		ExpiredSessions: expired,
+		TempMedia:       sweep,
+	}, nil
+}
@@ -21,7 +21,14 @@ func (m *Module) CreateProfile(ctx context.Context, accountID uuid.UUID, usernam
 	}
 
 	if err = m.repo.Transaction(ctx, func(ctx context.Context) error {
-		profile, err = m.repo.InsertProfile(ctx, accountID, username)
+		tombstone, err := m.repo.DeleteProfileTombstone(ctx, accountID)
+		if err != nil {
+			return err
+		}
+
+		// the versions of a profile created again continue above its deletion,
+		// so that consumers do not take the new profile for stale events
+		profile, err = m.repo.InsertProfile(ctx, accountID, username, tombstone.Version+1)
 		if err != nil {
 			return err
 		}
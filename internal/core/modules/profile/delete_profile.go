@@ -8,12 +8,19 @@ import (
 
 func (m *Module) DeleteProfile(ctx context.Context, userID uuid.UUID) error {
 	return m.repo.Transaction(ctx, func(ctx context.Context) error {
-		err := m.repo.DeleteProfile(ctx, userID)
+		version, err := m.repo.DeleteProfile(ctx, userID)
 		if err != nil {
 			return err
 		}
 
-		err = m.messanger.WriteProfileDeleted(ctx, userID)
+		// the next snapshot publishes a tombstone for the profile, the deletion is
+		// one more change of the profile and a recreated profile continues above it
+		tombstone, err := m.repo.UpsertProfileTombstone(ctx, userID, version+1)
+		if err != nil {
+			return err
+		}
+
+		err = m.messanger.WriteProfileDeleted(ctx, tombstone)
 		if err != nil {
 			return err
 		}
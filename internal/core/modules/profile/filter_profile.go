@@ -2,15 +2,45 @@ package profile
 
 import (
 	"context"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/netbill/profiles-svc/internal/core/models"
 	"github.com/netbill/restkit/pagi"
 )
 
+const (
+	SortByUsername  = "username"
+	SortByCreatedAt = "created_at"
+	SortByUpdatedAt = "updated_at"
+	SortByOfficial  = "official"
+)
+
+// SortFields lists the fields profiles can be sorted by.
+var SortFields = []string{
+	SortByUsername,
+	SortByCreatedAt,
+	SortByUpdatedAt,
+	SortByOfficial,
+}
+
 type FilterParams struct {
+	AccountID *uuid.UUID
+	Username  *string
+	Official  *bool
+
 	UsernamePrefix  *string
 	PseudonymPrefix *string
-	Verified        *bool
+
+	// Search matches username, pseudonym and description by similarity
+	// and orders the result by relevance before Sort is applied.
+	Search *string
+	// BoostOfficial ranks official profiles higher in Search results.
+	BoostOfficial bool
+
+	// Sort is applied in order, ties are always broken by account id.
+	// Defaults to created_at ascending.
+	Sort []pagi.SortField
 }
 
 func (m *Module) FilterProfile(
@@ -25,3 +55,50 @@ func (m *Module) FilterProfile(
 
 	return collection, nil
 }
+
+// Cursor points at the profile on the edge of a keyset page. It carries
+// every sortable field, so it stays valid for any FilterParams.Sort.
+type Cursor struct {
+	AccountID uuid.UUID
+	Username  string
+	Official  bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func NewCursor(p models.Profile) Cursor {
+	return Cursor{
+		AccountID: p.AccountID,
+		Username:  p.Username,
+		Official:  p.Official,
+		CreatedAt: p.CreatedAt,
+		UpdatedAt: p.UpdatedAt,
+	}
+}
+
+type CursorParams struct {
+	Limit uint
+	// From is nil for the first page.
+	From *Cursor
+	// Backward reads the page preceding From instead of the one following it.
+	Backward bool
+}
+
+type CursorPage struct {
+	Data    []models.Profile
+	HasNext bool
+	HasPrev bool
+}
+
+func (m *Module) FilterProfileByCursor(
+	ctx context.Context,
+	params FilterParams,
+	cursor CursorParams,
+) (CursorPage, error) {
+	collection, err := m.repo.FilterProfilesByCursor(ctx, params, cursor)
+	if err != nil {
+		return CursorPage{}, err
+	}
+
+	return collection, nil
+}
@@ -14,3 +14,21 @@ func (m *Module) GetProfileByAccountID(ctx context.Context, userID uuid.UUID) (m
 func (m *Module) GetProfileByUsername(ctx context.Context, username string) (models.Profile, error) {
 	return m.repo.GetProfileByUsername(ctx, username)
 }
+
+func (m *Module) GetProfilesByAccountIDs(ctx context.Context, accountIDs []uuid.UUID) ([]models.Profile, error) {
+	seen := make(map[uuid.UUID]struct{}, len(accountIDs))
+	unique := make([]uuid.UUID, 0, len(accountIDs))
+	for _, id := range accountIDs {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+
+	if len(unique) == 0 {
+		return []models.Profile{}, nil
+	}
+
+	return m.repo.GetProfilesByAccountIDs(ctx, unique)
+}
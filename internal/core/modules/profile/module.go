@@ -2,57 +2,131 @@ package profile
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/netbill/logium"
 
 	"github.com/netbill/profiles-svc/internal/core/models"
 	"github.com/netbill/restkit/pagi"
 )
 
 type Module struct {
+	log *logium.Logger
+
 	repo      repo
 	messanger messanger
 	token     token
 	bucket    bucket
+
+	bannedWords       bannedWords
+	uploadSessionTTL  time.Duration
+	avatarHistorySize uint
+}
+
+type Config struct {
+	// BannedWords are rejected in pseudonyms and descriptions, matched as whole words.
+	// An entry of several words is a phrase, matched as the same words in a row.
+	BannedWords []string
+
+	// UploadSessionTTL is how long an update session stays open,
+	// it should match the upload token lifetime.
+	UploadSessionTTL time.Duration
+
+	// AvatarHistorySize is how many accepted avatars are kept per account
+	// for restoring, the current one included. Values below 1 are treated as 1.
+	AvatarHistorySize uint
 }
 
-func New(repo repo, messanger messanger, token token, bucket bucket) *Module {
+func New(
+	log *logium.Logger,
+	repo repo,
+	messanger messanger,
+	token token,
+	bucket bucket,
+	cfg Config,
+) *Module {
 	return &Module{
+		log: log,
+
 		repo:      repo,
 		messanger: messanger,
 		token:     token,
 		bucket:    bucket,
+
+		bannedWords:       newBannedWords(cfg.BannedWords),
+		uploadSessionTTL:  cfg.UploadSessionTTL,
+		avatarHistorySize: max(cfg.AvatarHistorySize, 1),
 	}
 }
 
 type repo interface {
-	InsertProfile(ctx context.Context, userID uuid.UUID, username string) (models.Profile, error)
+	InsertProfile(ctx context.Context, userID uuid.UUID, username string, version int64) (models.Profile, error)
 
 	GetProfileByAccountID(ctx context.Context, userID uuid.UUID) (models.Profile, error)
 	GetProfileByUsername(ctx context.Context, username string) (models.Profile, error)
+	GetProfilesByAccountIDs(ctx context.Context, accountIDs []uuid.UUID) ([]models.Profile, error)
 
 	UpdateProfile(ctx context.Context, userID uuid.UUID, params UpdateParams) (models.Profile, error)
-	UpdateProfileAvatar(ctx context.Context, userID uuid.UUID, avatarURL string) (models.Profile, error)
+	UpdateProfileAvatar(
+		ctx context.Context,
+		userID uuid.UUID,
+		avatarURL string,
+		variants map[string]string,
+		version int64,
+	) (models.Profile, error)
 	DeleteProfileAvatar(ctx context.Context, userID uuid.UUID) (models.Profile, error)
 
 	UpdateProfileUsername(ctx context.Context, userID uuid.UUID, username string) (models.Profile, error)
 	UpdateProfileOfficial(ctx context.Context, userID uuid.UUID, official bool) (models.Profile, error)
 
-	DeleteProfile(ctx context.Context, userID uuid.UUID) error
+	DeleteProfile(ctx context.Context, userID uuid.UUID) (int64, error)
+
+	ListProfilesAfter(ctx context.Context, after *uuid.UUID, limit uint) ([]models.Profile, error)
+	UpsertProfileTombstone(ctx context.Context, accountID uuid.UUID, version int64) (models.ProfileTombstone, error)
+	DeleteProfileTombstone(ctx context.Context, accountID uuid.UUID) (models.ProfileTombstone, error)
+	ListProfileTombstones(ctx context.Context, after *uuid.UUID, limit uint) ([]models.ProfileTombstone, error)
+
+	InsertUploadSession(
+		ctx context.Context,
+		accountID, sessionID uuid.UUID,
+		expiresAt time.Time,
+	) (models.UploadSession, error)
+	GetUploadSession(ctx context.Context, accountID, sessionID uuid.UUID) (models.UploadSession, error)
+	UpdateUploadSessionStatus(
+		ctx context.Context,
+		sessionID uuid.UUID,
+		status string,
+	) (models.UploadSession, error)
+	ExpireUploadSessions(ctx context.Context, before time.Time) (int64, error)
+
+	UpsertProfileAvatar(ctx context.Context, avatar models.ProfileAvatar) (models.ProfileAvatar, error)
+	GetProfileAvatar(ctx context.Context, accountID, avatarID uuid.UUID) (models.ProfileAvatar, error)
+	GetProfileAvatarByKey(ctx context.Context, accountID uuid.UUID, key string) (models.ProfileAvatar, error)
+	ListProfileAvatars(ctx context.Context, accountID uuid.UUID) ([]models.ProfileAvatar, error)
+	PruneProfileAvatars(ctx context.Context, accountID uuid.UUID, keep uint) ([]models.ProfileAvatar, error)
 
 	FilterProfiles(
 		ctx context.Context,
 		params FilterParams,
 		limit, offset uint,
 	) (pagi.Page[[]models.Profile], error)
+	FilterProfilesByCursor(
+		ctx context.Context,
+		params FilterParams,
+		cursor CursorParams,
+	) (CursorPage, error)
 
 	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
 type messanger interface {
 	WriteProfileCreated(ctx context.Context, profile models.Profile) error
-	WriteProfileUpdated(ctx context.Context, profile models.Profile) error
-	WriteProfileDeleted(ctx context.Context, accountID uuid.UUID) error
+	WriteProfileUpdated(ctx context.Context, profile models.Profile, changes models.ProfileChanges) error
+	WriteProfileDeleted(ctx context.Context, tombstone models.ProfileTombstone) error
+
+	WriteProfileSnapshot(ctx context.Context, profile models.Profile) error
+	WriteProfileSnapshotTombstone(ctx context.Context, tombstone models.ProfileTombstone) error
 }
 
 type token interface {
@@ -65,26 +139,35 @@ type token interface {
 type bucket interface {
 	GetPreloadLinkForProfileMedia(
 		ctx context.Context,
+		kind models.ProfileMediaKind,
 		accountID, sessionID uuid.UUID,
 	) (links models.UpdateProfileMediaLinks, err error)
 
-	CancelUpdateProfileAvatar(
+	CancelUpdateProfileMedia(
 		ctx context.Context,
+		kind models.ProfileMediaKind,
 		accountID, sessionID uuid.UUID,
 	) error
 
-	DeleteProfileAvatar(
+	DeleteProfileMedia(
 		ctx context.Context,
-		accountID uuid.UUID,
+		key string,
+		variants map[string]string,
 	) error
 
 	AcceptUpdateProfileMedia(
 		ctx context.Context,
+		kind models.ProfileMediaKind,
 		accountID, sessionID uuid.UUID,
-	) (string, error)
+	) (models.ProfileMedia, error)
 
 	CleanProfileMediaSession(
 		ctx context.Context,
 		accountID, sessionID uuid.UUID,
 	) error
+
+	DeleteAbandonedProfileMediaUploads(
+		ctx context.Context,
+		before time.Time,
+	) (models.TempMediaSweep, error)
 }
@@ -0,0 +1,84 @@
+package profile
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/netbill/profiles-svc/internal/core/errx"
+	"github.com/netbill/profiles-svc/internal/core/models"
+)
+
+// ListProfileAvatars returns the account's avatar history, newest first,
+// together with the profile to tell which of them is the current one.
+func (m *Module) ListProfileAvatars(
+	ctx context.Context,
+	accountID uuid.UUID,
+) ([]models.ProfileAvatar, models.Profile, error) {
+	profile, err := m.GetProfileByAccountID(ctx, accountID)
+	if err != nil {
+		return nil, models.Profile{}, err
+	}
+
+	avatars, err := m.repo.ListProfileAvatars(ctx, accountID)
+	if err != nil {
+		return nil, models.Profile{}, err
+	}
+
+	return avatars, profile, nil
+}
+
+// RestoreProfileAvatar makes an avatar from the account's history the current one.
+// version, when set, is the profile version the restore is based on, the restore
+// fails with errx.ErrorProfileVersionMismatch if it is stale.
+func (m *Module) RestoreProfileAvatar(
+	ctx context.Context,
+	accountID, avatarID uuid.UUID,
+	version *int64,
+) (profile models.Profile, err error) {
+	avatar, err := m.repo.GetProfileAvatar(ctx, accountID, avatarID)
+	if err != nil {
+		return models.Profile{}, err
+	}
+
+	var previous models.Profile
+	if err = m.repo.Transaction(ctx, func(ctx context.Context) error {
+		previous, err = m.repo.GetProfileByAccountID(ctx, accountID)
+		if err != nil {
+			return err
+		}
+
+		if version != nil && *version != previous.Version {
+			return errx.ErrorProfileVersionMismatch.Raise(
+				fmt.Errorf("profile version is %d, expected %d", previous.Version, *version),
+			)
+		}
+
+		// the update applies only to the version read above,
+		// so the changes and the replaced avatar are the ones of that version
+		profile, err = m.repo.UpdateProfileAvatar(ctx, accountID, avatar.Key, avatar.Variants, previous.Version)
+		if err != nil {
+			return err
+		}
+
+		// moves the restored avatar to the top so that pruning keeps it
+		if _, err = m.repo.UpsertProfileAvatar(ctx, avatar); err != nil {
+			return err
+		}
+
+		err = m.messanger.WriteProfileUpdated(ctx, profile, models.DiffProfiles(previous, profile))
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}); err != nil {
+		return models.Profile{}, err
+	}
+
+	if !sameMediaKey(previous.Avatar, profile.Avatar) {
+		m.deleteUnreferencedMedia(ctx, accountID, previous.Avatar, previous.AvatarVariants)
+	}
+
+	return profile, nil
+}
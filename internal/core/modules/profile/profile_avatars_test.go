@@ -0,0 +1,159 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/netbill/logium"
+	"github.com/netbill/profiles-svc/internal/core/errx"
+	"github.com/netbill/profiles-svc/internal/core/models"
+)
+
+// avatarRepo restores avatars from the history of mediaRepo, the profile is only
+// updated when it is still at the expected version. committed runs before the
+// restore transaction, concurrent inside it, each as another update committing then.
+type avatarRepo struct {
+	mediaRepo
+
+	avatars    map[uuid.UUID]string
+	committed  func(r *mediaRepo)
+	concurrent func(r *mediaRepo)
+}
+
+func (r *avatarRepo) GetProfileAvatar(_ context.Context, _ uuid.UUID, avatarID uuid.UUID) (models.ProfileAvatar, error) {
+	key, ok := r.avatars[avatarID]
+	if !ok {
+		return models.ProfileAvatar{}, errx.ErrorProfileAvatarNotFound.Raise(fmt.Errorf("avatar %s not found", avatarID))
+	}
+	if r.committed != nil {
+		r.committed(&r.mediaRepo)
+	}
+
+	return models.ProfileAvatar{ID: avatarID, Key: key}, nil
+}
+
+func (r *avatarRepo) UpdateProfileAvatar(
+	_ context.Context,
+	_ uuid.UUID,
+	key string,
+	_ map[string]string,
+	version int64,
+) (models.Profile, error) {
+	if r.concurrent != nil {
+		r.concurrent(&r.mediaRepo)
+	}
+	if r.profile.Version != version {
+		return models.Profile{}, errx.ErrorProfileVersionMismatch.Raise(
+			fmt.Errorf("profile version is %d, expected %d", r.profile.Version, version),
+		)
+	}
+
+	r.profile.Avatar = &key
+	r.profile.Version++
+	return r.profile, nil
+}
+
+// avatarMessanger records the changes of the written profile updates.
+type avatarMessanger struct {
+	messanger
+
+	changes []models.ProfileChanges
+}
+
+func (m *avatarMessanger) WriteProfileUpdated(_ context.Context, _ models.Profile, changes models.ProfileChanges) error {
+	m.changes = append(m.changes, changes)
+	return nil
+}
+
+func TestRestoreProfileAvatar(t *testing.T) {
+	accountID := uuid.New()
+	avatarID := uuid.New()
+
+	key := func(s string) *string { return &s }
+	version := func(v int64) *int64 { return &v }
+
+	tests := []struct {
+		name        string
+		history     []string
+		version     *int64
+		committed   func(r *mediaRepo)
+		concurrent  func(r *mediaRepo)
+		wantErr     error
+		wantDeleted []string
+	}{
+		{
+			name:    "restores the avatar",
+			history: []string{"avatar-1", "avatar-2"},
+			version: version(3),
+		},
+		{
+			name:    "stale version is rejected",
+			history: []string{"avatar-1", "avatar-2"},
+			version: version(2),
+			wantErr: errx.ErrorProfileVersionMismatch,
+		},
+		{
+			name:    "profile read in the transaction",
+			history: []string{"avatar-1", "avatar-2"},
+			// another update replaces the avatar after the avatar lookup
+			committed: func(r *mediaRepo) {
+				r.profile.Avatar = key("avatar-3")
+				r.profile.Version++
+			},
+			wantDeleted: []string{"avatar-3"},
+		},
+		{
+			name:    "update committed inside the transaction is not overwritten",
+			history: []string{"avatar-1", "avatar-2"},
+			concurrent: func(r *mediaRepo) {
+				r.profile.Avatar = key("avatar-3")
+				r.profile.Version++
+			},
+			wantErr: errx.ErrorProfileVersionMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &avatarRepo{
+				mediaRepo: mediaRepo{
+					profile: models.Profile{AccountID: accountID, Avatar: key("avatar-2"), Version: 3},
+					history: tt.history,
+				},
+				avatars:    map[uuid.UUID]string{avatarID: "avatar-1"},
+				committed:  tt.committed,
+				concurrent: tt.concurrent,
+			}
+			b := &mediaBucket{}
+			msg := &avatarMessanger{}
+			m := New(logium.New(), r, msg, nil, b, Config{})
+
+			profile, err := m.RestoreProfileAvatar(context.Background(), accountID, avatarID, tt.version)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RestoreProfileAvatar() error = %v, want %v", err, tt.wantErr)
+			}
+			if fmt.Sprint(b.deleted) != fmt.Sprint(tt.wantDeleted) {
+				t.Fatalf("deleted media = %v, want %v", b.deleted, tt.wantDeleted)
+			}
+			if tt.wantErr != nil {
+				if len(msg.changes) != 0 {
+					t.Fatalf("written changes = %v, want none", msg.changes)
+				}
+				return
+			}
+
+			if profile.Avatar == nil || *profile.Avatar != "avatar-1" {
+				t.Fatalf("avatar = %v, want avatar-1", profile.Avatar)
+			}
+			if len(msg.changes) != 1 {
+				t.Fatalf("written changes = %v, want one update", msg.changes)
+			}
+			if _, ok := msg.changes[0].Get(models.ProfileFieldAvatar); !ok {
+				t.Fatalf("written changes = %v, want an avatar change", msg.changes[0])
+			}
+		})
+	}
+}
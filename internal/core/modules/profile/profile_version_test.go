@@ -0,0 +1,159 @@
+package profile
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/netbill/logium"
+	"github.com/netbill/profiles-svc/internal/core/errx"
+	"github.com/netbill/profiles-svc/internal/core/models"
+)
+
+// versionRepo keeps profiles and tombstones like the database does.
+type versionRepo struct {
+	repo
+
+	profiles   map[uuid.UUID]models.Profile
+	tombstones map[uuid.UUID]models.ProfileTombstone
+}
+
+func (r *versionRepo) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (r *versionRepo) GetProfileByAccountID(_ context.Context, accountID uuid.UUID) (models.Profile, error) {
+	p, ok := r.profiles[accountID]
+	if !ok {
+		return models.Profile{}, errx.ErrorProfileNotFound.Raise(fmt.Errorf("profile %s not found", accountID))
+	}
+
+	return p, nil
+}
+
+func (r *versionRepo) InsertProfile(
+	_ context.Context,
+	accountID uuid.UUID,
+	username string,
+	version int64,
+) (models.Profile, error) {
+	p := models.Profile{AccountID: accountID, Username: username, Version: version}
+	r.profiles[accountID] = p
+	return p, nil
+}
+
+func (r *versionRepo) DeleteProfile(_ context.Context, accountID uuid.UUID) (int64, error) {
+	p := r.profiles[accountID]
+	delete(r.profiles, accountID)
+	return p.Version, nil
+}
+
+func (r *versionRepo) UpsertProfileTombstone(
+	_ context.Context,
+	accountID uuid.UUID,
+	version int64,
+) (models.ProfileTombstone, error) {
+	t := models.ProfileTombstone{AccountID: accountID, Version: version, DeletedAt: time.Now().UTC()}
+	if prev, ok := r.tombstones[accountID]; ok && prev.Version > version {
+		t.Version = prev.Version
+	}
+
+	r.tombstones[accountID] = t
+	return t, nil
+}
+
+func (r *versionRepo) DeleteProfileTombstone(_ context.Context, accountID uuid.UUID) (models.ProfileTombstone, error) {
+	t := r.tombstones[accountID]
+	delete(r.tombstones, accountID)
+	return t, nil
+}
+
+// versionMessanger records the versions of the events written.
+type versionMessanger struct {
+	messanger
+
+	created []int64
+	deleted []int64
+}
+
+func (m *versionMessanger) WriteProfileCreated(_ context.Context, profile models.Profile) error {
+	m.created = append(m.created, profile.Version)
+	return nil
+}
+
+func (m *versionMessanger) WriteProfileDeleted(_ context.Context, tombstone models.ProfileTombstone) error {
+	m.deleted = append(m.deleted, tombstone.Version)
+	return nil
+}
+
+func TestProfileVersionAcrossDeletion(t *testing.T) {
+	accountID := uuid.New()
+
+	tests := []struct {
+		name        string
+		steps       []string
+		wantCreated []int64
+		wantDeleted []int64
+	}{
+		{
+			name:        "new account",
+			steps:       []string{"create"},
+			wantCreated: []int64{1},
+		},
+		{
+			name:        "recreated after delete",
+			steps:       []string{"create", "delete", "create"},
+			wantCreated: []int64{1, 3},
+			wantDeleted: []int64{2},
+		},
+		{
+			name:        "recreated after updates",
+			steps:       []string{"create", "update", "update", "delete", "create"},
+			wantCreated: []int64{1, 5},
+			wantDeleted: []int64{4},
+		},
+		{
+			name:        "repeated delete keeps the version",
+			steps:       []string{"create", "delete", "delete", "create"},
+			wantCreated: []int64{1, 3},
+			wantDeleted: []int64{2, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &versionRepo{
+				profiles:   make(map[uuid.UUID]models.Profile),
+				tombstones: make(map[uuid.UUID]models.ProfileTombstone),
+			}
+			msg := &versionMessanger{}
+			m := New(logium.New(), r, msg, nil, nil, Config{})
+
+			for _, step := range tt.steps {
+				var err error
+				switch step {
+				case "create":
+					_, err = m.CreateProfile(context.Background(), accountID, "alice")
+				case "update":
+					p := r.profiles[accountID]
+					p.Version++
+					r.profiles[accountID] = p
+				case "delete":
+					err = m.DeleteProfile(context.Background(), accountID)
+				}
+				if err != nil {
+					t.Fatalf("%s error = %v", step, err)
+				}
+			}
+
+			if fmt.Sprint(msg.created) != fmt.Sprint(tt.wantCreated) {
+				t.Fatalf("created versions = %v, want %v", msg.created, tt.wantCreated)
+			}
+			if fmt.Sprint(msg.deleted) != fmt.Sprint(tt.wantDeleted) {
+				t.Fatalf("deleted versions = %v, want %v", msg.deleted, tt.wantDeleted)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package profile
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const defaultSnapshotBatchSize = 500
+
+type snapshotPhase string
+
+const (
+	snapshotPhaseProfiles   snapshotPhase = "profiles"
+	snapshotPhaseTombstones snapshotPhase = "tombstones"
+)
+
+// snapshotCursor is the position of a snapshot run, profiles are published
+// first and the tombstones of deleted profiles after them.
+type snapshotCursor struct {
+	phase snapshotPhase
+	after *uuid.UUID
+}
+
+func (c snapshotCursor) token() string {
+	after := ""
+	if c.after != nil {
+		after = c.after.String()
+	}
+
+	return base64.RawURLEncoding.EncodeToString([]byte(string(c.phase) + ":" + after))
+}
+
+func parseSnapshotToken(token string) (snapshotCursor, error) {
+	if token == "" {
+		return snapshotCursor{phase: snapshotPhaseProfiles}, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return snapshotCursor{}, fmt.Errorf("invalid snapshot resume token: %w", err)
+	}
+
+	phase, after, ok := strings.Cut(string(raw), ":")
+	if !ok || (phase != string(snapshotPhaseProfiles) && phase != string(snapshotPhaseTombstones)) {
+		return snapshotCursor{}, fmt.Errorf("invalid snapshot resume token %q", token)
+	}
+
+	cursor := snapshotCursor{phase: snapshotPhase(phase)}
+	if after != "" {
+		id, err := uuid.Parse(after)
+		if err != nil {
+			return snapshotCursor{}, fmt.Errorf("invalid snapshot resume token %q: %w", token, err)
+		}
+		cursor.after = &id
+	}
+
+	return cursor, nil
+}
+
+type SnapshotParams struct {
+	// BatchSize is how many rows are published in one transaction.
+	BatchSize uint
+	// ResumeToken continues a previous run after its last published batch,
+	// an empty token starts from the first profile.
+	ResumeToken string
+	// Progress is called after every published batch.
+	Progress func(SnapshotProgress)
+}
+
+type SnapshotProgress struct {
+	Profiles   uint
+	Tombstones uint
+	// ResumeToken continues the snapshot after the last published batch.
+	ResumeToken string
+	Done        bool
+}
+
+// PublishSnapshot writes the state of every profile and a tombstone for every
+// deleted profile to the snapshot topic through the outbox, in batches ordered
+// by account id.
+func (m *Module) PublishSnapshot(ctx context.Context, params SnapshotParams) (SnapshotProgress, error) {
+	cursor, err := parseSnapshotToken(params.ResumeToken)
+	if err != nil {
+		return SnapshotProgress{}, err
+	}
+
+	batchSize := params.BatchSize
+	if batchSize == 0 {
+		batchSize = defaultSnapshotBatchSize
+	}
+
+	progress := SnapshotProgress{ResumeToken: cursor.token()}
+	report := func() {
+		if params.Progress != nil {
+			params.Progress(progress)
+		}
+	}
+
+	for cursor.phase == snapshotPhaseProfiles {
+		profiles, err := m.repo.ListProfilesAfter(ctx, cursor.after, batchSize)
+		if err != nil {
+			return progress, err
+		}
+
+		if err = m.repo.Transaction(ctx, func(ctx context.Context) error {
+			for _, profile := range profiles {
+				if err := m.messanger.WriteProfileSnapshot(ctx, profile); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}); err != nil {
+			return progress, err
+		}
+
+		if uint(len(profiles)) < batchSize {
+			cursor = snapshotCursor{phase: snapshotPhaseTombstones}
+		} else {
+			cursor.after = &profiles[len(profiles)-1].AccountID
+		}
+
+		progress.Profiles += uint(len(profiles))
+		progress.ResumeToken = cursor.token()
+		report()
+	}
+
+	for {
+		tombstones, err := m.repo.ListProfileTombstones(ctx, cursor.after, batchSize)
+		if err != nil {
+			return progress, err
+		}
+
+		if err = m.repo.Transaction(ctx, func(ctx context.Context) error {
+			for _, tombstone := range tombstones {
+				if err := m.messanger.WriteProfileSnapshotTombstone(ctx, tombstone); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}); err != nil {
+			return progress, err
+		}
+
+		if len(tombstones) > 0 {
+			cursor.after = &tombstones[len(tombstones)-1].AccountID
+		}
+
+		progress.Tombstones += uint(len(tombstones))
+		progress.ResumeToken = cursor.token()
+		progress.Done = uint(len(tombstones)) < batchSize
+		report()
+
+		if progress.Done {
+			return progress, nil
+		}
+	}
+}
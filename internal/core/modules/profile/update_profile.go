@@ -3,6 +3,8 @@ package profile
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/netbill/profiles-svc/internal/core/errx"
@@ -19,15 +21,29 @@ func (m *Module) OpenProfileUpdateSession(
 	}
 
 	uploadSessionID := uuid.New()
-	links, err := m.bucket.GetPreloadLinkForProfileMedia(
+	_, err = m.repo.InsertUploadSession(
 		ctx,
 		accountID,
 		uploadSessionID,
+		time.Now().UTC().Add(m.uploadSessionTTL),
 	)
 	if err != nil {
 		return models.UpdateProfileMedia{}, models.Profile{}, err
 	}
 
+	links := make(map[models.ProfileMediaKind]models.UpdateProfileMediaLinks, len(models.ProfileMediaKinds))
+	for _, kind := range models.ProfileMediaKinds {
+		links[kind], err = m.bucket.GetPreloadLinkForProfileMedia(
+			ctx,
+			kind,
+			accountID,
+			uploadSessionID,
+		)
+		if err != nil {
+			return models.UpdateProfileMedia{}, models.Profile{}, err
+		}
+	}
+
 	uploadToken, err := m.token.NewUploadProfileMediaToken(accountID, uploadSessionID)
 	if err != nil {
 		return models.UpdateProfileMedia{}, models.Profile{}, err
@@ -40,9 +56,20 @@ func (m *Module) OpenProfileUpdateSession(
 	}, profile, nil
 }
 
+// UpdateField is an update of a nullable profile field. The zero value leaves
+// the stored value untouched, a set field with a nil Value clears it.
+type UpdateField[T any] struct {
+	Set   bool
+	Value *T
+}
+
 type UpdateParams struct {
-	Pseudonym   *string
-	Description *string
+	Pseudonym   UpdateField[string]
+	Description UpdateField[string]
+
+	// Version, when set, is the profile version the update is based on,
+	// the update fails with errx.ErrorProfileVersionMismatch if it is stale.
+	Version *int64
 
 	Media UpdateMediaParams
 }
@@ -50,16 +77,48 @@ type UpdateParams struct {
 type UpdateMediaParams struct {
 	UploadSessionID uuid.UUID
 
-	DeleteAvatar bool
-	avatarKey    *string
+	// Delete lists the media kinds removed from the profile,
+	// an upload of a removed kind in the same session is ignored.
+	Delete map[models.ProfileMediaKind]bool
+
+	values map[models.ProfileMediaKind]mediaValue
 }
 
-func (p UpdateParams) GetUpdatedAvatar() *string {
-	if p.Media.DeleteAvatar {
-		return nil
+// mediaValue is what a media slot of the profile is set to.
+type mediaValue struct {
+	key      *string
+	variants map[string]string
+}
+
+func profileMediaValue(profile models.Profile, kind models.ProfileMediaKind) mediaValue {
+	switch kind {
+	case models.ProfileMediaKindAvatar:
+		return mediaValue{key: profile.Avatar, variants: profile.AvatarVariants}
+	case models.ProfileMediaKindBanner:
+		return mediaValue{key: profile.Banner}
+	default:
+		return mediaValue{}
+	}
+}
+
+func (p UpdateParams) getUpdatedMedia(kind models.ProfileMediaKind) mediaValue {
+	if p.Media.Delete[kind] {
+		return mediaValue{}
 	}
 
-	return p.Media.avatarKey
+	return p.Media.values[kind]
+}
+
+func (p UpdateParams) GetUpdatedAvatar() *string {
+	return p.getUpdatedMedia(models.ProfileMediaKindAvatar).key
+}
+
+func (p UpdateParams) GetUpdatedAvatarVariants() map[string]string {
+	return p.getUpdatedMedia(models.ProfileMediaKindAvatar).variants
+}
+
+func (p UpdateParams) GetUpdatedBanner() *string {
+	return p.getUpdatedMedia(models.ProfileMediaKindBanner).key
 }
 
 func (m *Module) UpdateProfile(
@@ -67,74 +126,228 @@ func (m *Module) UpdateProfile(
 	accountID uuid.UUID,
 	params UpdateParams,
 ) (profile models.Profile, err error) {
+	if err = m.validateUpdateParams(&params); err != nil {
+		return models.Profile{}, err
+	}
+
 	profile, err = m.GetProfileByAccountID(ctx, accountID)
 	if err != nil {
 		return models.Profile{}, err
 	}
 
-	params.Media.avatarKey = profile.Avatar
-	switch params.Media.DeleteAvatar {
-	case true:
-		if err = m.bucket.DeleteProfileAvatar(
-			ctx,
-			accountID,
-		); err != nil {
-			return models.Profile{}, err
+	if params.Version != nil && *params.Version != profile.Version {
+		return models.Profile{}, errx.ErrorProfileVersionMismatch.Raise(
+			fmt.Errorf("profile version is %d, expected %d", profile.Version, *params.Version),
+		)
+	}
+
+	if _, err = m.getActiveUploadSession(ctx, accountID, params.Media.UploadSessionID); err != nil {
+		return models.Profile{}, err
+	}
+
+	previous := profile
+	accepted := make(map[models.ProfileMediaKind]models.ProfileMedia, len(models.ProfileMediaKinds))
+
+	params.Media.values = make(map[models.ProfileMediaKind]mediaValue, len(models.ProfileMediaKinds))
+	for _, kind := range models.ProfileMediaKinds {
+		params.Media.values[kind] = profileMediaValue(profile, kind)
+		if params.Media.Delete[kind] {
+			// the stored media is deleted once the profile no longer references it
+			continue
 		}
 
-		params.Media.avatarKey = nil
-	case false:
-		avatar, err := m.bucket.AcceptUpdateProfileMedia(
+		media, err := m.bucket.AcceptUpdateProfileMedia(
 			ctx,
+			kind,
 			accountID,
 			params.Media.UploadSessionID,
 		)
 		switch {
 		case errors.Is(err, errx.ErrorNoContentUploaded):
-			// No new avatar uploaded, keep the existing one
+			// Nothing uploaded for this kind, keep the existing one
 		case err != nil:
+			m.discardAcceptedMedia(ctx, accountID, accepted)
 			return models.Profile{}, err
 		default:
-			params.Media.avatarKey = &avatar
+			accepted[kind] = media
+			params.Media.values[kind] = mediaValue{key: &media.Key, variants: media.Variants}
 		}
 	}
 
-	err = m.bucket.CleanProfileMediaSession(
-		ctx,
-		accountID,
-		params.Media.UploadSessionID,
-	)
-	if err != nil {
-		return models.Profile{}, err
+	if len(accepted) > 0 {
+		_, err = m.repo.UpdateUploadSessionStatus(
+			ctx,
+			params.Media.UploadSessionID,
+			models.UploadSessionStatusUploaded,
+		)
+		if err != nil {
+			m.discardAcceptedMedia(ctx, accountID, accepted)
+			return models.Profile{}, err
+		}
 	}
 
+	var pruned []models.ProfileAvatar
 	if err = m.repo.Transaction(ctx, func(ctx context.Context) error {
+		// confirming first makes a replayed confirm fail before the profile is touched
+		_, err = m.repo.UpdateUploadSessionStatus(
+			ctx,
+			params.Media.UploadSessionID,
+			models.UploadSessionStatusConfirmed,
+		)
+		if err != nil {
+			return err
+		}
+
 		profile, err = m.repo.UpdateProfile(ctx, accountID, params)
 		if err != nil {
 			return err
 		}
 
-		err = m.messanger.WriteProfileUpdated(ctx, profile)
+		if avatar, ok := accepted[models.ProfileMediaKindAvatar]; ok {
+			_, err = m.repo.UpsertProfileAvatar(ctx, models.ProfileAvatar{
+				AccountID: accountID,
+				Key:       avatar.Key,
+				Variants:  avatar.Variants,
+				Hash:      avatar.Hash,
+				Width:     avatar.Width,
+				Height:    avatar.Height,
+			})
+			if err != nil {
+				return err
+			}
+
+			pruned, err = m.repo.PruneProfileAvatars(ctx, accountID, m.avatarHistorySize)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = m.messanger.WriteProfileUpdated(ctx, profile, models.DiffProfiles(previous, profile))
 		if err != nil {
 			return err
 		}
 
 		return nil
 	}); err != nil {
+		m.discardAcceptedMedia(ctx, accountID, accepted)
 		return models.Profile{}, err
 	}
 
+	// the uploads stay in the session until the update is committed, a failed
+	// confirm can be retried; leftovers are removed by the janitor sweep
+	err = m.bucket.CleanProfileMediaSession(ctx, accountID, params.Media.UploadSessionID)
+	if err != nil {
+		m.log.WithError(err).Errorf("failed to delete uploads of confirmed session %s", params.Media.UploadSessionID)
+	}
+
+	for _, avatar := range pruned {
+		m.deleteUnreferencedMedia(ctx, accountID, &avatar.Key, avatar.Variants)
+	}
+
+	for _, kind := range models.ProfileMediaKinds {
+		before := profileMediaValue(previous, kind)
+		if !sameMediaKey(before.key, profileMediaValue(profile, kind).key) {
+			m.deleteUnreferencedMedia(ctx, accountID, before.key, before.variants)
+		}
+	}
+
 	return profile, nil
 }
 
-func (m *Module) DeleteUploadProfileAvatarInSession(
+func sameMediaKey(a, b *string) bool {
+	return a == nil && b == nil || a != nil && b != nil && *a == *b
+}
+
+// discardAcceptedMedia deletes the uploads accepted for an update that did not happen.
+func (m *Module) discardAcceptedMedia(
+	ctx context.Context,
+	accountID uuid.UUID,
+	accepted map[models.ProfileMediaKind]models.ProfileMedia,
+) {
+	for _, media := range accepted {
+		m.deleteUnreferencedMedia(ctx, accountID, &media.Key, media.Variants)
+	}
+}
+
+// deleteUnreferencedMedia deletes a media version unless the committed profile or the
+// avatar history still references it. Keys are content-addressed, a concurrent update
+// may have committed the same key, so both are read again right before deleting.
+func (m *Module) deleteUnreferencedMedia(
+	ctx context.Context,
+	accountID uuid.UUID,
+	key *string,
+	variants map[string]string,
+) {
+	if key == nil {
+		return
+	}
+
+	profile, err := m.repo.GetProfileByAccountID(ctx, accountID)
+	switch {
+	case errors.Is(err, errx.ErrorProfileNotFound):
+	case err != nil:
+		m.log.WithError(err).Errorf("failed to check profile references of media %s", *key)
+		return
+	case sameMediaKey(profile.Avatar, key) || sameMediaKey(profile.Banner, key):
+		return
+	}
+
+	_, err = m.repo.GetProfileAvatarByKey(ctx, accountID, *key)
+	switch {
+	case errors.Is(err, errx.ErrorProfileAvatarNotFound):
+	case err != nil:
+		m.log.WithError(err).Errorf("failed to check profile avatar history for %s", *key)
+		return
+	default:
+		return
+	}
+
+	// the profile is already saved at this point, a failure only leaves an orphaned object behind
+	if err = m.bucket.DeleteProfileMedia(ctx, *key, variants); err != nil {
+		m.log.WithError(err).Errorf("failed to delete unreferenced profile media %s", *key)
+	}
+}
+
+// DeleteUploadProfileMediaInSession drops the upload of one media kind, the session
+// stays open so that the other kinds can still be confirmed or uploaded again.
+func (m *Module) DeleteUploadProfileMediaInSession(
+	ctx context.Context,
+	kind models.ProfileMediaKind,
+	accountID, sessionID uuid.UUID,
+) error {
+	_, err := m.getActiveUploadSession(ctx, accountID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	err = m.bucket.CancelUpdateProfileMedia(ctx, kind, accountID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CancelProfileUpdateSession closes the session as cancelled without touching the profile
+// and drops everything uploaded in it. A cancelled session can not be confirmed any more.
+func (m *Module) CancelProfileUpdateSession(
 	ctx context.Context,
 	accountID, sessionID uuid.UUID,
 ) error {
-	err := m.bucket.CancelUpdateProfileAvatar(ctx, accountID, sessionID)
+	_, err := m.getActiveUploadSession(ctx, accountID, sessionID)
 	if err != nil {
 		return err
 	}
 
+	_, err = m.repo.UpdateUploadSessionStatus(ctx, sessionID, models.UploadSessionStatusCancelled)
+	if err != nil {
+		return err
+	}
+
+	// the session is closed already, leftovers are removed by the janitor sweep
+	if err = m.bucket.CleanProfileMediaSession(ctx, accountID, sessionID); err != nil {
+		m.log.WithError(err).Errorf("failed to delete uploads of cancelled session %s", sessionID)
+	}
+
 	return nil
 }
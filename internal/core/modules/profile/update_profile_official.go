@@ -9,12 +9,17 @@ import (
 
 func (m *Module) UpdateProfileOfficial(ctx context.Context, accountID uuid.UUID, official bool) (profile models.Profile, err error) {
 	if err = m.repo.Transaction(ctx, func(ctx context.Context) error {
+		previous, err := m.repo.GetProfileByAccountID(ctx, accountID)
+		if err != nil {
+			return err
+		}
+
 		profile, err = m.repo.UpdateProfileOfficial(ctx, accountID, official)
 		if err != nil {
 			return err
 		}
 
-		err = m.messanger.WriteProfileUpdated(ctx, profile)
+		err = m.messanger.WriteProfileUpdated(ctx, profile, models.DiffProfiles(previous, profile))
 		if err != nil {
 			return err
 		}
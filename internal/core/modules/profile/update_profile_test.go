@@ -0,0 +1,200 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/netbill/logium"
+	"github.com/netbill/profiles-svc/internal/core/errx"
+	"github.com/netbill/profiles-svc/internal/core/models"
+)
+
+// mediaRepo keeps the committed profile and avatar history of one account,
+// concurrent runs after UpdateProfile as another confirm committing right after it.
+type mediaRepo struct {
+	sessionRepo
+
+	profile    models.Profile
+	history    []string
+	concurrent func(r *mediaRepo)
+	updateErr  error
+}
+
+func (r *mediaRepo) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (r *mediaRepo) GetProfileByAccountID(_ context.Context, _ uuid.UUID) (models.Profile, error) {
+	return r.profile, nil
+}
+
+func (r *mediaRepo) UpdateProfile(_ context.Context, _ uuid.UUID, params UpdateParams) (models.Profile, error) {
+	updated := r.profile
+	updated.Avatar = params.GetUpdatedAvatar()
+	updated.AvatarVariants = params.GetUpdatedAvatarVariants()
+	updated.Banner = params.GetUpdatedBanner()
+	updated.Version++
+
+	if r.updateErr == nil {
+		r.profile = updated
+	}
+	if r.concurrent != nil {
+		r.concurrent(r)
+	}
+
+	return updated, r.updateErr
+}
+
+func (r *mediaRepo) UpsertProfileAvatar(_ context.Context, avatar models.ProfileAvatar) (models.ProfileAvatar, error) {
+	if !slices.Contains(r.history, avatar.Key) {
+		r.history = append(r.history, avatar.Key)
+	}
+
+	return avatar, nil
+}
+
+func (r *mediaRepo) PruneProfileAvatars(context.Context, uuid.UUID, uint) ([]models.ProfileAvatar, error) {
+	return nil, nil
+}
+
+func (r *mediaRepo) GetProfileAvatarByKey(_ context.Context, _ uuid.UUID, key string) (models.ProfileAvatar, error) {
+	if !slices.Contains(r.history, key) {
+		return models.ProfileAvatar{}, errx.ErrorProfileAvatarNotFound.Raise(fmt.Errorf("avatar %s not found", key))
+	}
+
+	return models.ProfileAvatar{Key: key}, nil
+}
+
+// mediaBucket accepts the uploads of the session and records the deleted objects.
+type mediaBucket struct {
+	sessionBucket
+
+	uploads map[models.ProfileMediaKind]string
+	deleted []string
+}
+
+func (b *mediaBucket) AcceptUpdateProfileMedia(
+	_ context.Context,
+	kind models.ProfileMediaKind,
+	_, _ uuid.UUID,
+) (models.ProfileMedia, error) {
+	key, ok := b.uploads[kind]
+	if !ok {
+		return models.ProfileMedia{}, errx.ErrorNoContentUploaded.Raise(fmt.Errorf("no %s uploaded", kind))
+	}
+
+	return models.ProfileMedia{Key: key}, nil
+}
+
+func (b *mediaBucket) DeleteProfileMedia(_ context.Context, key string, _ map[string]string) error {
+	b.deleted = append(b.deleted, key)
+	return nil
+}
+
+type mediaMessanger struct {
+	messanger
+}
+
+func (mediaMessanger) WriteProfileUpdated(context.Context, models.Profile, models.ProfileChanges) error {
+	return nil
+}
+
+func TestUpdateProfileMediaCleanup(t *testing.T) {
+	accountID := uuid.New()
+	sessionID := uuid.New()
+
+	key := func(s string) *string { return &s }
+
+	tests := []struct {
+		name        string
+		profile     models.Profile
+		history     []string
+		uploads     map[models.ProfileMediaKind]string
+		concurrent  func(r *mediaRepo)
+		updateErr   error
+		wantErr     bool
+		wantDeleted []string
+		wantCleaned bool
+	}{
+		{
+			name:        "replaced banner is deleted",
+			profile:     models.Profile{Banner: key("banner-1")},
+			uploads:     map[models.ProfileMediaKind]string{models.ProfileMediaKindBanner: "banner-2"},
+			wantDeleted: []string{"banner-1"},
+			wantCleaned: true,
+		},
+		{
+			name:    "replaced banner committed again concurrently is kept",
+			profile: models.Profile{Banner: key("banner-1")},
+			uploads: map[models.ProfileMediaKind]string{models.ProfileMediaKindBanner: "banner-2"},
+			// another confirm uploads the old content again, its key is the same
+			concurrent:  func(r *mediaRepo) { r.profile.Banner = key("banner-1") },
+			wantCleaned: true,
+		},
+		{
+			name:        "replaced avatar kept by the history",
+			profile:     models.Profile{Avatar: key("avatar-1")},
+			history:     []string{"avatar-1"},
+			uploads:     map[models.ProfileMediaKind]string{models.ProfileMediaKindAvatar: "avatar-2"},
+			wantCleaned: true,
+		},
+		{
+			name:        "failed confirm discards the upload and keeps the session",
+			profile:     models.Profile{Avatar: key("avatar-1")},
+			uploads:     map[models.ProfileMediaKind]string{models.ProfileMediaKindAvatar: "avatar-2"},
+			updateErr:   errors.New("db is down"),
+			wantErr:     true,
+			wantDeleted: []string{"avatar-2"},
+		},
+		{
+			name:    "failed confirm keeps an upload a concurrent confirm committed",
+			profile: models.Profile{Avatar: key("avatar-1")},
+			uploads: map[models.ProfileMediaKind]string{models.ProfileMediaKindAvatar: "avatar-2"},
+			concurrent: func(r *mediaRepo) {
+				r.profile.Avatar = key("avatar-2")
+				r.history = append(r.history, "avatar-2")
+			},
+			updateErr: errx.ErrorProfileVersionMismatch.Raise(errors.New("profile changed")),
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &mediaRepo{
+				sessionRepo: sessionRepo{session: models.UploadSession{
+					ID:        sessionID,
+					AccountID: accountID,
+					Status:    models.UploadSessionStatusOpened,
+					ExpiresAt: time.Now().UTC().Add(time.Hour),
+				}},
+				profile:    tt.profile,
+				history:    tt.history,
+				concurrent: tt.concurrent,
+				updateErr:  tt.updateErr,
+			}
+			r.profile.AccountID = accountID
+			b := &mediaBucket{uploads: tt.uploads}
+			m := New(logium.New(), r, mediaMessanger{}, nil, b, Config{})
+
+			_, err := m.UpdateProfile(context.Background(), accountID, UpdateParams{
+				Media: UpdateMediaParams{UploadSessionID: sessionID},
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UpdateProfile() error = %v, want error %v", err, tt.wantErr)
+			}
+
+			if fmt.Sprint(b.deleted) != fmt.Sprint(tt.wantDeleted) {
+				t.Fatalf("deleted media = %v, want %v", b.deleted, tt.wantDeleted)
+			}
+			if b.cleaned != tt.wantCleaned {
+				t.Fatalf("session cleaned = %v, want %v", b.cleaned, tt.wantCleaned)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package profile
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/netbill/profiles-svc/internal/core/errx"
+	"github.com/netbill/profiles-svc/internal/core/models"
+)
+
+// getActiveUploadSession returns the account's upload session if it can still be used.
+func (m *Module) getActiveUploadSession(
+	ctx context.Context,
+	accountID, sessionID uuid.UUID,
+) (models.UploadSession, error) {
+	session, err := m.repo.GetUploadSession(ctx, accountID, sessionID)
+	if err != nil {
+		return models.UploadSession{}, err
+	}
+
+	switch {
+	case session.Status == models.UploadSessionStatusExpired,
+		session.IsActive() && !session.ExpiresAt.After(time.Now().UTC()):
+		return models.UploadSession{}, errx.ErrorUploadSessionExpired.Raise(
+			fmt.Errorf("upload session %s expired at %s", sessionID, session.ExpiresAt),
+		)
+	case !session.IsActive():
+		return models.UploadSession{}, errx.ErrorUploadSessionClosed.Raise(
+			fmt.Errorf("upload session %s is already %s", sessionID, session.Status),
+		)
+	}
+
+	return session, nil
+}
@@ -0,0 +1,207 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/netbill/logium"
+	"github.com/netbill/profiles-svc/internal/core/errx"
+	"github.com/netbill/profiles-svc/internal/core/models"
+)
+
+// sessionRepo keeps one upload session and moves it like the database does:
+// only an active, not yet expired session changes its status.
+type sessionRepo struct {
+	repo
+
+	session models.UploadSession
+}
+
+func (r *sessionRepo) GetUploadSession(_ context.Context, accountID, sessionID uuid.UUID) (models.UploadSession, error) {
+	if r.session.IsNil() || r.session.ID != sessionID || r.session.AccountID != accountID {
+		return models.UploadSession{}, errx.ErrorUploadSessionNotFound.Raise(
+			fmt.Errorf("upload session %s not found", sessionID),
+		)
+	}
+
+	return r.session, nil
+}
+
+func (r *sessionRepo) UpdateUploadSessionStatus(
+	_ context.Context,
+	sessionID uuid.UUID,
+	status string,
+) (models.UploadSession, error) {
+	if r.session.ID != sessionID || !r.session.IsActive() || !r.session.ExpiresAt.After(time.Now().UTC()) {
+		return models.UploadSession{}, errx.ErrorUploadSessionClosed.Raise(
+			fmt.Errorf("upload session %s is not active", sessionID),
+		)
+	}
+
+	r.session.Status = status
+	return r.session, nil
+}
+
+// sessionBucket records the temp uploads dropped from the session.
+type sessionBucket struct {
+	bucket
+
+	cancelled []models.ProfileMediaKind
+	cleaned   bool
+}
+
+func (b *sessionBucket) CancelUpdateProfileMedia(
+	_ context.Context,
+	kind models.ProfileMediaKind,
+	_, _ uuid.UUID,
+) error {
+	b.cancelled = append(b.cancelled, kind)
+	return nil
+}
+
+func (b *sessionBucket) CleanProfileMediaSession(_ context.Context, _, _ uuid.UUID) error {
+	b.cleaned = true
+	return nil
+}
+
+func TestUploadSessionTransitions(t *testing.T) {
+	accountID := uuid.New()
+	sessionID := uuid.New()
+	now := time.Now().UTC()
+
+	session := func(status string, expiresAt time.Time) models.UploadSession {
+		return models.UploadSession{
+			ID:        sessionID,
+			AccountID: accountID,
+			Status:    status,
+			ExpiresAt: expiresAt,
+		}
+	}
+
+	tests := []struct {
+		name       string
+		session    models.UploadSession
+		action     func(m *Module) error
+		wantErr    error
+		wantStatus string
+		wantClean  bool
+		wantKinds  []models.ProfileMediaKind
+	}{
+		{
+			name:    "cancel opened session",
+			session: session(models.UploadSessionStatusOpened, now.Add(time.Hour)),
+			action: func(m *Module) error {
+				return m.CancelProfileUpdateSession(context.Background(), accountID, sessionID)
+			},
+			wantStatus: models.UploadSessionStatusCancelled,
+			wantClean:  true,
+		},
+		{
+			name:    "cancel uploaded session",
+			session: session(models.UploadSessionStatusUploaded, now.Add(time.Hour)),
+			action: func(m *Module) error {
+				return m.CancelProfileUpdateSession(context.Background(), accountID, sessionID)
+			},
+			wantStatus: models.UploadSessionStatusCancelled,
+			wantClean:  true,
+		},
+		{
+			name:    "cancel confirmed session",
+			session: session(models.UploadSessionStatusConfirmed, now.Add(time.Hour)),
+			action: func(m *Module) error {
+				return m.CancelProfileUpdateSession(context.Background(), accountID, sessionID)
+			},
+			wantErr:    errx.ErrorUploadSessionClosed,
+			wantStatus: models.UploadSessionStatusConfirmed,
+		},
+		{
+			name:    "cancel cancelled session",
+			session: session(models.UploadSessionStatusCancelled, now.Add(time.Hour)),
+			action: func(m *Module) error {
+				return m.CancelProfileUpdateSession(context.Background(), accountID, sessionID)
+			},
+			wantErr:    errx.ErrorUploadSessionClosed,
+			wantStatus: models.UploadSessionStatusCancelled,
+		},
+		{
+			name:    "cancel session past its expiry",
+			session: session(models.UploadSessionStatusOpened, now.Add(-time.Minute)),
+			action: func(m *Module) error {
+				return m.CancelProfileUpdateSession(context.Background(), accountID, sessionID)
+			},
+			wantErr:    errx.ErrorUploadSessionExpired,
+			wantStatus: models.UploadSessionStatusOpened,
+		},
+		{
+			name:    "cancel expired session",
+			session: session(models.UploadSessionStatusExpired, now.Add(-time.Minute)),
+			action: func(m *Module) error {
+				return m.CancelProfileUpdateSession(context.Background(), accountID, sessionID)
+			},
+			wantErr:    errx.ErrorUploadSessionExpired,
+			wantStatus: models.UploadSessionStatusExpired,
+		},
+		{
+			name:    "cancel session of another account",
+			session: session(models.UploadSessionStatusOpened, now.Add(time.Hour)),
+			action: func(m *Module) error {
+				return m.CancelProfileUpdateSession(context.Background(), uuid.New(), sessionID)
+			},
+			wantErr:    errx.ErrorUploadSessionNotFound,
+			wantStatus: models.UploadSessionStatusOpened,
+		},
+		{
+			name:    "delete one upload keeps session open",
+			session: session(models.UploadSessionStatusOpened, now.Add(time.Hour)),
+			action: func(m *Module) error {
+				return m.DeleteUploadProfileMediaInSession(
+					context.Background(), models.ProfileMediaKindBanner, accountID, sessionID,
+				)
+			},
+			wantStatus: models.UploadSessionStatusOpened,
+			wantKinds:  []models.ProfileMediaKind{models.ProfileMediaKindBanner},
+		},
+		{
+			name:    "delete upload in confirmed session",
+			session: session(models.UploadSessionStatusConfirmed, now.Add(time.Hour)),
+			action: func(m *Module) error {
+				return m.DeleteUploadProfileMediaInSession(
+					context.Background(), models.ProfileMediaKindAvatar, accountID, sessionID,
+				)
+			},
+			wantErr:    errx.ErrorUploadSessionClosed,
+			wantStatus: models.UploadSessionStatusConfirmed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &sessionRepo{session: tt.session}
+			b := &sessionBucket{}
+			m := New(logium.New(), r, nil, nil, b, Config{})
+
+			err := tt.action(m)
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.wantErr != nil && !errors.Is(err, tt.wantErr):
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+
+			if r.session.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", r.session.Status, tt.wantStatus)
+			}
+			if b.cleaned != tt.wantClean {
+				t.Errorf("session uploads cleaned = %v, want %v", b.cleaned, tt.wantClean)
+			}
+			if !slices.Equal(b.cancelled, tt.wantKinds) {
+				t.Errorf("cancelled uploads = %v, want %v", b.cancelled, tt.wantKinds)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package profile
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/netbill/profiles-svc/internal/core/errx"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	PseudonymMaxLength   = 128
+	DescriptionMaxLength = 255
+)
+
+// invisibleRunes are stripped from user provided text. ZWJ and ZWNJ are kept
+// on purpose, emoji sequences and several scripts depend on them.
+var invisibleRunes = map[rune]struct{}{
+	'\u00AD': {}, // soft hyphen
+	'\u180E': {}, // mongolian vowel separator
+	'\u200B': {}, // zero width space
+	'\u200E': {}, // left-to-right mark
+	'\u200F': {}, // right-to-left mark
+	'\u202A': {}, // bidi embedding and override controls
+	'\u202B': {},
+	'\u202C': {},
+	'\u202D': {},
+	'\u202E': {},
+	'\u2060': {}, // word joiner
+	'\u2066': {}, // bidi isolate controls
+	'\u2067': {},
+	'\u2068': {},
+	'\u2069': {},
+	'\uFEFF': {}, // zero width no-break space
+}
+
+// sanitizeText normalizes text to NFC, strips control and invisible characters
+// and trims surrounding whitespace. Line breaks survive only if multiline is set.
+func sanitizeText(s string, multiline bool) string {
+	s = norm.NFC.String(s)
+
+	s = strings.Map(func(r rune) rune {
+		if _, ok := invisibleRunes[r]; ok {
+			return -1
+		}
+		if r == '\n' && multiline {
+			return r
+		}
+		if unicode.IsControl(r) {
+			return -1
+		}
+
+		return r
+	}, s)
+
+	return strings.TrimSpace(s)
+}
+
+// bannedWords holds the banned entries as sequences of words joined by a space,
+// an entry of several words is a phrase matched as consecutive words.
+type bannedWords struct {
+	phrases  map[string]struct{}
+	maxWords int
+}
+
+func newBannedWords(entries []string) bannedWords {
+	res := bannedWords{phrases: make(map[string]struct{}, len(entries))}
+	for _, e := range entries {
+		words := splitWords(sanitizeText(e, false))
+		if len(words) == 0 {
+			continue
+		}
+
+		res.phrases[strings.Join(words, " ")] = struct{}{}
+		res.maxWords = max(res.maxWords, len(words))
+	}
+
+	return res
+}
+
+// splitWords lowercases s and splits it into words, anything but letters and numbers separates them.
+func splitWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// containsBannedWord matches whole words and phrases only, case-insensitively.
+// The words of a phrase may be separated by any punctuation or whitespace.
+func (m *Module) containsBannedWord(s string) (string, bool) {
+	if len(m.bannedWords.phrases) == 0 {
+		return "", false
+	}
+
+	words := splitWords(s)
+	for i := range words {
+		for n := 1; n <= m.bannedWords.maxWords && i+n <= len(words); n++ {
+			phrase := strings.Join(words[i:i+n], " ")
+			if _, ok := m.bannedWords.phrases[phrase]; ok {
+				return phrase, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// validateUpdateParams sanitizes the text fields of params in place, blank values become nil.
+func (m *Module) validateUpdateParams(params *UpdateParams) error {
+	if params.Pseudonym.Set && params.Pseudonym.Value != nil {
+		v := sanitizeText(*params.Pseudonym.Value, false)
+
+		if n := utf8.RuneCountInString(v); n > PseudonymMaxLength {
+			return errx.ErrorProfilePseudonymTooLong.Raise(
+				fmt.Errorf("pseudonym is %d characters long, max %d", n, PseudonymMaxLength),
+			)
+		}
+		if w, ok := m.containsBannedWord(v); ok {
+			return errx.ErrorProfilePseudonymNotAllowed.Raise(
+				fmt.Errorf("pseudonym contains banned word %q", w),
+			)
+		}
+
+		params.Pseudonym.Value = &v
+		if v == "" {
+			params.Pseudonym.Value = nil
+		}
+	}
+
+	if params.Description.Set && params.Description.Value != nil {
+		v := sanitizeText(*params.Description.Value, true)
+
+		if n := utf8.RuneCountInString(v); n > DescriptionMaxLength {
+			return errx.ErrorProfileDescriptionTooLong.Raise(
+				fmt.Errorf("description is %d characters long, max %d", n, DescriptionMaxLength),
+			)
+		}
+		if w, ok := m.containsBannedWord(v); ok {
+			return errx.ErrorProfileDescriptionNotAllowed.Raise(
+				fmt.Errorf("description contains banned word %q", w),
+			)
+		}
+
+		params.Description.Value = &v
+		if v == "" {
+			params.Description.Value = nil
+		}
+	}
+
+	return nil
+}
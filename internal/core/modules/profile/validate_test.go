@@ -0,0 +1,144 @@
+package profile
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/netbill/profiles-svc/internal/core/errx"
+)
+
+func TestSanitizeText(t *testing.T) {
+	tests := []struct {
+		name      string
+		in        string
+		multiline bool
+		want      string
+	}{
+		{name: "trimmed", in: "  alice \t", want: "alice"},
+		{name: "NFC", in: "Cafe\u0301", want: "Caf\u00e9"},
+		{name: "zero width and bidi", in: "al\u200bi\u202ece\ufeff", want: "alice"},
+		{name: "joiners kept", in: "\U0001F468\u200d\U0001F469", want: "\U0001F468\u200d\U0001F469"},
+		{name: "control stripped", in: "a\x00b\x07c", want: "abc"},
+		{name: "line break single line", in: "a\nb", want: "ab"},
+		{name: "line break multiline", in: "a\nb\r", multiline: true, want: "a\nb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizeText(tt.in, tt.multiline); got != tt.want {
+				t.Fatalf("sanitizeText(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContainsBannedWord(t *testing.T) {
+	m := &Module{bannedWords: newBannedWords([]string{"Spam", " buy  now ", "free-money", "  "})}
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "clean", in: "hello there", want: ""},
+		{name: "word", in: "no SPAM here", want: "spam"},
+		{name: "part of a word", in: "spammer", want: ""},
+		{name: "phrase", in: "Buy now, please", want: "buy now"},
+		{name: "phrase across punctuation", in: "buy...now", want: "buy now"},
+		{name: "phrase words apart", in: "buy it now", want: ""},
+		{name: "hyphenated entry", in: "get free money", want: "free money"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := m.containsBannedWord(tt.in)
+			if ok != (tt.want != "") || got != tt.want {
+				t.Fatalf("containsBannedWord(%q) = %q, %v, want %q", tt.in, got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateUpdateParams(t *testing.T) {
+	m := &Module{bannedWords: newBannedWords([]string{"spam"})}
+
+	set := func(v string) UpdateField[string] {
+		return UpdateField[string]{Set: true, Value: &v}
+	}
+
+	tests := []struct {
+		name            string
+		params          UpdateParams
+		wantErr         error
+		wantPseudonym   *string
+		wantDescription *string
+	}{
+		{
+			name:            "normalized",
+			params:          UpdateParams{Pseudonym: set("  Ali\u200bce "), Description: set(" line\none ")},
+			wantPseudonym:   ptr("Alice"),
+			wantDescription: ptr("line\none"),
+		},
+		{
+			name:   "blank becomes unset",
+			params: UpdateParams{Pseudonym: set(" \u200b "), Description: set("\t")},
+		},
+		{
+			name:          "max length in runes",
+			params:        UpdateParams{Pseudonym: set(strings.Repeat("ж", PseudonymMaxLength))},
+			wantPseudonym: ptr(strings.Repeat("ж", PseudonymMaxLength)),
+		},
+		{
+			name:    "pseudonym too long",
+			params:  UpdateParams{Pseudonym: set(strings.Repeat("a", PseudonymMaxLength+1))},
+			wantErr: errx.ErrorProfilePseudonymTooLong,
+		},
+		{
+			name:    "description too long",
+			params:  UpdateParams{Description: set(strings.Repeat("a", DescriptionMaxLength+1))},
+			wantErr: errx.ErrorProfileDescriptionTooLong,
+		},
+		{
+			name:    "pseudonym banned",
+			params:  UpdateParams{Pseudonym: set("Spam King")},
+			wantErr: errx.ErrorProfilePseudonymNotAllowed,
+		},
+		{
+			name:    "description banned",
+			params:  UpdateParams{Description: set("all spam")},
+			wantErr: errx.ErrorProfileDescriptionNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := m.validateUpdateParams(&tt.params)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("validateUpdateParams() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if !equalOptional(tt.params.Pseudonym.Value, tt.wantPseudonym) {
+				t.Fatalf("pseudonym = %v, want %v", tt.params.Pseudonym.Value, tt.wantPseudonym)
+			}
+			if !equalOptional(tt.params.Description.Value, tt.wantDescription) {
+				t.Fatalf("description = %v, want %v", tt.params.Description.Value, tt.wantDescription)
+			}
+		})
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+func equalOptional(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
@@ -0,0 +1,98 @@
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	"golang.org/x/image/draw"
+)
+
+const jpegQuality = 85
+
+type Encoder struct {
+	ContentType string
+	Encode      func(w io.Writer, img image.Image) error
+}
+
+// encoders are the output formats images can be encoded to.
+var encoders = map[string]Encoder{
+	"jpeg": {
+		ContentType: "image/jpeg",
+		Encode: func(w io.Writer, img image.Image) error {
+			return jpeg.Encode(w, img, &jpeg.Options{Quality: jpegQuality})
+		},
+	},
+	"png": {
+		ContentType: "image/png",
+		Encode: func(w io.Writer, img image.Image) error {
+			return png.Encode(w, img)
+		},
+	},
+	"gif": {
+		ContentType: "image/gif",
+		Encode: func(w io.Writer, img image.Image) error {
+			return gif.Encode(w, img, nil)
+		},
+	},
+}
+
+func GetEncoder(format string) (Encoder, error) {
+	enc, ok := encoders[format]
+	if !ok {
+		return Encoder{}, fmt.Errorf("image format %q has no encoder", format)
+	}
+
+	return enc, nil
+}
+
+// Encode encodes img to format, returns the encoded bytes and their content type.
+func Encode(img image.Image, format string) ([]byte, string, error) {
+	enc, err := GetEncoder(format)
+	if err != nil {
+		return nil, "", err
+	}
+
+	buf := &bytes.Buffer{}
+	if err = enc.Encode(buf, img); err != nil {
+		return nil, "", fmt.Errorf("encoding image to %s: %w", format, err)
+	}
+
+	return buf.Bytes(), enc.ContentType, nil
+}
+
+// Decode decodes data and applies its EXIF orientation, returns the image and its format.
+// The decoded image carries no metadata, encoding it again drops EXIF, ICC profiles
+// and comments of the source. Only the first frame of an animated GIF is kept.
+func Decode(data []byte) (image.Image, string, error) {
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("decoding image: %w", err)
+	}
+
+	if format == "jpeg" {
+		img = Orient(img, Orientation(data))
+	}
+
+	return img, format, nil
+}
+
+// Square crops the centered square of img and scales it to size x size.
+func Square(img image.Image, size int) image.Image {
+	b := img.Bounds()
+
+	side := min(b.Dx(), b.Dy())
+	crop := image.Rect(0, 0, side, side).Add(image.Pt(
+		b.Min.X+(b.Dx()-side)/2,
+		b.Min.Y+(b.Dy()-side)/2,
+	))
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, crop, draw.Src, nil)
+
+	return dst
+}
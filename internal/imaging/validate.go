@@ -0,0 +1,193 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"strings"
+)
+
+var (
+	ErrTruncated     = errors.New("image is truncated")
+	ErrCorrupted     = errors.New("image is corrupted")
+	ErrTooManyPixels = errors.New("image has too many pixels")
+	ErrTooManyFrames = errors.New("image has too many frames")
+)
+
+// Limits bound the resources a decode may take, zero values disable a limit.
+type Limits struct {
+	// MaxPixels caps width*height of the image and of every animation frame.
+	MaxPixels uint64
+	// MaxFrames caps the number of frames of an animated image.
+	MaxFrames int
+}
+
+// DecodeStrict decodes the whole image like Decode, but checks the limits before
+// any pixel data is allocated and reports malformed input as ErrTruncated or ErrCorrupted.
+func DecodeStrict(data []byte, limits Limits) (image.Image, string, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", decodeError(err)
+	}
+
+	if err = checkPixels(cfg, limits.MaxPixels); err != nil {
+		return nil, "", err
+	}
+
+	if format == "gif" {
+		if err = checkGIFFrames(data, cfg, limits); err != nil {
+			return nil, "", err
+		}
+	}
+
+	img, format, err := Decode(data)
+	if err != nil {
+		return nil, "", decodeError(err)
+	}
+
+	return img, format, nil
+}
+
+// DecodeBounded decodes the image like Decode once its header shows it within maxPixels,
+// a zero maxPixels disables the check. Unlike DecodeStrict it does not classify malformed input.
+func DecodeBounded(data []byte, maxPixels uint64) (image.Image, string, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("decoding image config: %w", err)
+	}
+
+	if err = checkPixels(cfg, maxPixels); err != nil {
+		return nil, "", err
+	}
+
+	return Decode(data)
+}
+
+func checkPixels(cfg image.Config, maxPixels uint64) error {
+	if maxPixels > 0 && uint64(cfg.Width)*uint64(cfg.Height) > maxPixels {
+		return fmt.Errorf("%w: %dx%d, max %d", ErrTooManyPixels, cfg.Width, cfg.Height, maxPixels)
+	}
+
+	return nil
+}
+
+func decodeError(err error) error {
+	// image/png reports a short read as a FormatError, so its message is checked too
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) ||
+		strings.Contains(err.Error(), io.ErrUnexpectedEOF.Error()) {
+		return fmt.Errorf("%w: %v", ErrTruncated, err)
+	}
+
+	return fmt.Errorf("%w: %v", ErrCorrupted, err)
+}
+
+// checkGIFFrames walks the GIF block structure without decompressing the frames,
+// so a file with thousands of frames is rejected before it is decoded.
+func checkGIFFrames(data []byte, screen image.Config, limits Limits) error {
+	r := &gifReader{data: data}
+
+	// header and logical screen descriptor
+	if !r.skip(6) {
+		return fmt.Errorf("%w: gif header", ErrTruncated)
+	}
+	lsd, ok := r.read(7)
+	if !ok {
+		return fmt.Errorf("%w: gif logical screen descriptor", ErrTruncated)
+	}
+	if lsd[4]&0x80 != 0 && !r.skip(3*(1<<(lsd[4]&0x07+1))) {
+		return fmt.Errorf("%w: gif global color table", ErrTruncated)
+	}
+
+	frames := 0
+	for {
+		block, ok := r.byte()
+		if !ok {
+			return fmt.Errorf("%w: gif has no trailer", ErrTruncated)
+		}
+
+		switch block {
+		case 0x3B: // trailer
+			return nil
+		case 0x21: // extension
+			if !r.skip(1) || !r.skipSubBlocks() {
+				return fmt.Errorf("%w: gif extension", ErrTruncated)
+			}
+		case 0x2C: // image descriptor
+			desc, ok := r.read(9)
+			if !ok {
+				return fmt.Errorf("%w: gif image descriptor", ErrTruncated)
+			}
+
+			frames++
+			if limits.MaxFrames > 0 && frames > limits.MaxFrames {
+				return fmt.Errorf("%w: more than %d", ErrTooManyFrames, limits.MaxFrames)
+			}
+
+			left := int(desc[0]) | int(desc[1])<<8
+			top := int(desc[2]) | int(desc[3])<<8
+			width := int(desc[4]) | int(desc[5])<<8
+			height := int(desc[6]) | int(desc[7])<<8
+			if left+width > screen.Width || top+height > screen.Height {
+				return fmt.Errorf("%w: gif frame %d is outside of the image", ErrCorrupted, frames)
+			}
+			if limits.MaxPixels > 0 && uint64(width)*uint64(height) > limits.MaxPixels {
+				return fmt.Errorf("%w: gif frame %d is %dx%d", ErrTooManyPixels, frames, width, height)
+			}
+
+			if desc[8]&0x80 != 0 && !r.skip(3*(1<<(desc[8]&0x07+1))) {
+				return fmt.Errorf("%w: gif local color table", ErrTruncated)
+			}
+			// LZW minimum code size, then the compressed frame data
+			if !r.skip(1) || !r.skipSubBlocks() {
+				return fmt.Errorf("%w: gif frame %d data", ErrTruncated, frames)
+			}
+		default:
+			return fmt.Errorf("%w: unknown gif block 0x%02x", ErrCorrupted, block)
+		}
+	}
+}
+
+type gifReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *gifReader) byte() (byte, bool) {
+	if r.pos >= len(r.data) {
+		return 0, false
+	}
+	r.pos++
+
+	return r.data[r.pos-1], true
+}
+
+func (r *gifReader) read(n int) ([]byte, bool) {
+	if r.pos+n > len(r.data) {
+		return nil, false
+	}
+	r.pos += n
+
+	return r.data[r.pos-n : r.pos], true
+}
+
+func (r *gifReader) skip(n int) bool {
+	_, ok := r.read(n)
+	return ok
+}
+
+func (r *gifReader) skipSubBlocks() bool {
+	for {
+		size, ok := r.byte()
+		if !ok {
+			return false
+		}
+		if size == 0 {
+			return true
+		}
+		if !r.skip(int(size)) {
+			return false
+		}
+	}
+}
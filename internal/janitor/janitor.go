@@ -0,0 +1,80 @@
+package janitor
+
+import (
+	"context"
+	"time"
+
+	"github.com/netbill/logium"
+	"github.com/netbill/profiles-svc/internal/core/modules/profile"
+)
+
+type Janitor struct {
+	log    *logium.Logger
+	domain domain
+
+	interval time.Duration
+	maxAge   time.Duration
+}
+
+type Config struct {
+	// Interval between two sweeps, zero disables periodic sweeps.
+	Interval time.Duration
+	// MaxAge is the age after which a temp upload counts as abandoned.
+	MaxAge time.Duration
+}
+
+func New(log *logium.Logger, domain domain, cfg Config) *Janitor {
+	return &Janitor{
+		log:      log,
+		domain:   domain,
+		interval: cfg.Interval,
+		maxAge:   cfg.MaxAge,
+	}
+}
+
+type domain interface {
+	CleanAbandonedUploads(ctx context.Context, olderThan time.Duration) (profile.CleanUploadsReport, error)
+}
+
+// Run sweeps abandoned uploads every interval until ctx is done.
+func (j *Janitor) Run(ctx context.Context) {
+	if j.interval <= 0 {
+		j.log.Warnf("upload janitor interval is not set, periodic sweeps are disabled")
+		return
+	}
+
+	j.log.Infof("starting upload janitor, interval %s, max age %s", j.interval, j.maxAge)
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		_, _ = j.RunOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			j.log.Warnf("upload janitor stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce makes a single sweep and logs its result.
+func (j *Janitor) RunOnce(ctx context.Context) (profile.CleanUploadsReport, error) {
+	report, err := j.domain.CleanAbandonedUploads(ctx, j.maxAge)
+
+	log := j.log.
+		WithField("expired_sessions", report.ExpiredSessions).
+		WithField("scanned", report.TempMedia.Scanned).
+		WithField("deleted", report.TempMedia.Deleted).
+		WithField("failed", report.TempMedia.Failed)
+	if err != nil {
+		log.WithError(err).Error("failed to clean abandoned uploads")
+		return report, err
+	}
+
+	log.Info("abandoned uploads cleaned")
+
+	return report, nil
+}
@@ -2,6 +2,7 @@ package messenger
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -26,6 +27,17 @@ type handlers interface {
 	) inbox.EventStatus
 }
 
+// unknownInboxEvent fails an event no handler is registered for.
+func (m *Messenger) unknownInboxEvent(ctx context.Context, ev inbox.Event) inbox.EventStatus {
+	err := m.RecordInboxEventFailure(ctx, ev.ID, fmt.Sprintf("no handler for event type %s version %d", ev.Type, ev.Version))
+	if err != nil {
+		m.log.WithError(err).Errorf("failed to store failure reason of inbox event %s", ev.ID)
+		return inbox.EventStatusPending
+	}
+
+	return inbox.EventStatusFailed
+}
+
 func (m *Messenger) RunConsumer(ctx context.Context, handlers handlers) {
 	wg := &sync.WaitGroup{}
 	run := func(f func()) {
@@ -59,9 +71,7 @@ func (m *Messenger) RunConsumer(ctx context.Context, handlers handlers) {
 			RetryDelay: 1 * time.Minute,
 			MinSleep:   100 * time.Millisecond,
 			MaxSleep:   1 * time.Second,
-			Unknown: func(ctx context.Context, ev inbox.Event) inbox.EventStatus {
-				return inbox.EventStatusFailed
-			},
+			Unknown:    m.unknownInboxEvent,
 		},
 	)
 
@@ -78,9 +88,7 @@ func (m *Messenger) RunConsumer(ctx context.Context, handlers handlers) {
 			RetryDelay: 1 * time.Minute,
 			MinSleep:   100 * time.Millisecond,
 			MaxSleep:   1 * time.Second,
-			Unknown: func(ctx context.Context, ev inbox.Event) inbox.EventStatus {
-				return inbox.EventStatusFailed
-			},
+			Unknown:    m.unknownInboxEvent,
 		},
 	)
 
@@ -25,6 +25,16 @@ type AccountUsernameUpdatedPayload struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
+// AccountUsernameUpdatedPayloadV2 is version 2 of account.username.updated, new_username
+// became username and the previous username was added. The handler works with this
+// version, version 1 is upcast to it.
+type AccountUsernameUpdatedPayloadV2 struct {
+	AccountID   uuid.UUID `json:"account_id"`
+	Username    string    `json:"username"`
+	OldUsername string    `json:"old_username,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
 const AccountDeletedEvent = "account.deleted"
 
 type AccountDeletedPayload struct {
@@ -0,0 +1,61 @@
+package contracts
+
+import "fmt"
+
+func (p *AccountCreatedPayload) UnmarshalProto(data []byte) error {
+	*p = AccountCreatedPayload{}
+
+	return rangeProtoFields(data, func(f protoField) (err error) {
+		switch f.num {
+		case 1:
+			p.AccountID, err = f.uuid()
+		case 2:
+			p.Username, err = f.string()
+		case 3:
+			p.CreatedAt, err = f.time()
+		}
+		if err != nil {
+			return fmt.Errorf("decoding account created payload: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (p *AccountUsernameUpdatedPayload) UnmarshalProto(data []byte) error {
+	*p = AccountUsernameUpdatedPayload{}
+
+	return rangeProtoFields(data, func(f protoField) (err error) {
+		switch f.num {
+		case 1:
+			p.AccountID, err = f.uuid()
+		case 2:
+			p.NewUsername, err = f.string()
+		case 3:
+			p.UpdatedAt, err = f.time()
+		}
+		if err != nil {
+			return fmt.Errorf("decoding account username updated payload: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (p *AccountDeletedPayload) UnmarshalProto(data []byte) error {
+	*p = AccountDeletedPayload{}
+
+	return rangeProtoFields(data, func(f protoField) (err error) {
+		switch f.num {
+		case 1:
+			p.AccountID, err = f.uuid()
+		case 2:
+			p.DeletedAt, err = f.time()
+		}
+		if err != nil {
+			return fmt.Errorf("decoding account deleted payload: %w", err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,25 @@
+package contracts
+
+import "fmt"
+
+func (p *AccountUsernameUpdatedPayloadV2) UnmarshalProto(data []byte) error {
+	*p = AccountUsernameUpdatedPayloadV2{}
+
+	return rangeProtoFields(data, func(f protoField) (err error) {
+		switch f.num {
+		case 1:
+			p.AccountID, err = f.uuid()
+		case 2:
+			p.Username, err = f.string()
+		case 3:
+			p.OldUsername, err = f.string()
+		case 4:
+			p.UpdatedAt, err = f.time()
+		}
+		if err != nil {
+			return fmt.Errorf("decoding account username updated v2 payload: %w", err)
+		}
+
+		return nil
+	})
+}
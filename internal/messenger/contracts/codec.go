@@ -0,0 +1,159 @@
+package contracts
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+)
+
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/x-protobuf"
+)
+
+const (
+	CodecJSON     = "json"
+	CodecProtobuf = "protobuf"
+)
+
+// Codec encodes event payloads, the content type goes to the content type header.
+type Codec interface {
+	ContentType() string
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+type JSONCodec struct{}
+
+func (JSONCodec) ContentType() string {
+	return ContentTypeJSON
+}
+
+func (JSONCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// ProtoMarshaler is a payload with a protobuf encoding,
+// its schema is in the .proto file next to the payload.
+type ProtoMarshaler interface {
+	MarshalProto() ([]byte, error)
+}
+
+type ProtoUnmarshaler interface {
+	UnmarshalProto(data []byte) error
+}
+
+type ProtobufCodec struct{}
+
+func (ProtobufCodec) ContentType() string {
+	return ContentTypeProtobuf
+}
+
+func (ProtobufCodec) Marshal(v any) ([]byte, error) {
+	m, ok := v.(ProtoMarshaler)
+	if !ok {
+		return nil, fmt.Errorf("%T has no protobuf encoding", v)
+	}
+
+	return m.MarshalProto()
+}
+
+func (ProtobufCodec) Unmarshal(data []byte, v any) error {
+	m, ok := v.(ProtoUnmarshaler)
+	if !ok {
+		return fmt.Errorf("%T has no protobuf decoding", v)
+	}
+
+	return m.UnmarshalProto(data)
+}
+
+func CodecByName(name string) (Codec, error) {
+	switch name {
+	case "", CodecJSON:
+		return JSONCodec{}, nil
+	case CodecProtobuf:
+		return ProtobufCodec{}, nil
+	default:
+		return nil, fmt.Errorf("unknown codec %q", name)
+	}
+}
+
+// CodecByContentType returns the codec of a content type header,
+// JSON when it is empty as older producers do not set it.
+func CodecByContentType(contentType string) (Codec, error) {
+	if contentType == "" {
+		return JSONCodec{}, nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("invalid content type %q: %w", contentType, err)
+	}
+
+	switch mediaType {
+	case ContentTypeJSON:
+		return JSONCodec{}, nil
+	case ContentTypeProtobuf, "application/protobuf":
+		return ProtobufCodec{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported content type %q", contentType)
+	}
+}
+
+// DetectContentType tells the encoding of a payload that came without a content type.
+// A payload is JSON only if it is valid JSON as a whole. The bytes are not trimmed,
+// '\n' is the tag of protobuf field 1 with the bytes wire type, and a protobuf
+// message of the contracts is never valid JSON as its length prefixes and tags
+// are not JSON tokens.
+func DetectContentType(data []byte) string {
+	if len(data) == 0 || json.Valid(data) {
+		return ContentTypeJSON
+	}
+
+	return ContentTypeProtobuf
+}
+
+// Decode decodes a payload by its content type, an empty content type is detected from the payload.
+func Decode(contentType string, data []byte, v any) error {
+	if contentType == "" {
+		contentType = DetectContentType(data)
+	}
+
+	codec, err := CodecByContentType(contentType)
+	if err != nil {
+		return err
+	}
+
+	return codec.Unmarshal(data, v)
+}
+
+// Codecs picks the codec payloads of a topic are written with.
+type Codecs struct {
+	topics map[string]Codec
+}
+
+// NewCodecs takes codec names by topic, topics not listed are written as JSON.
+func NewCodecs(byTopic map[string]string) (Codecs, error) {
+	topics := make(map[string]Codec, len(byTopic))
+	for topic, name := range byTopic {
+		codec, err := CodecByName(name)
+		if err != nil {
+			return Codecs{}, fmt.Errorf("codec for topic %s: %w", topic, err)
+		}
+		topics[topic] = codec
+	}
+
+	return Codecs{topics: topics}, nil
+}
+
+func (c Codecs) ForTopic(topic string) Codec {
+	if codec, ok := c.topics[topic]; ok {
+		return codec
+	}
+
+	return JSONCodec{}
+}
@@ -0,0 +1,152 @@
+package contracts
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestDecodeAccountPayloads(t *testing.T) {
+	accountID := uuid.New()
+	at := time.Date(2026, 10, 18, 12, 30, 15, 123000000, time.UTC)
+
+	// a username 123 bytes long makes the length prefix of field 2 a '{'
+	brace := strings.Repeat("u", '{')
+
+	protoCreated := func(username string) []byte {
+		b := appendProtoUUID(nil, 1, accountID)
+		b = appendProtoString(b, 2, username)
+		return appendProtoTime(b, 3, at)
+	}
+	jsonOf := func(v any) []byte {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("json.Marshal() error = %v", err)
+		}
+		return data
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		data        []byte
+		into        func() any
+		want        any
+	}{
+		{
+			name:        "protobuf created",
+			contentType: ContentTypeProtobuf,
+			data:        protoCreated("alice"),
+			into:        func() any { return &AccountCreatedPayload{} },
+			want:        &AccountCreatedPayload{AccountID: accountID, Username: "alice", CreatedAt: at},
+		},
+		{
+			name:        "protobuf created detected",
+			contentType: "",
+			data:        protoCreated("alice"),
+			into:        func() any { return &AccountCreatedPayload{} },
+			want:        &AccountCreatedPayload{AccountID: accountID, Username: "alice", CreatedAt: at},
+		},
+		{
+			name:        "protobuf with brace length detected",
+			contentType: "",
+			data:        protoCreated(brace),
+			into:        func() any { return &AccountCreatedPayload{} },
+			want:        &AccountCreatedPayload{AccountID: accountID, Username: brace, CreatedAt: at},
+		},
+		{
+			name:        "protobuf username updated",
+			contentType: ContentTypeProtobuf + "; charset=binary",
+			data: appendProtoTime(
+				appendProtoString(appendProtoUUID(nil, 1, accountID), 2, "bob"), 3, at,
+			),
+			into: func() any { return &AccountUsernameUpdatedPayload{} },
+			want: &AccountUsernameUpdatedPayload{AccountID: accountID, NewUsername: "bob", UpdatedAt: at},
+		},
+		{
+			name:        "protobuf deleted skips unknown fields",
+			contentType: "application/protobuf",
+			data: appendProtoString(
+				appendProtoTime(appendProtoUUID(nil, 1, accountID), 2, at), 9, "future field",
+			),
+			into: func() any { return &AccountDeletedPayload{} },
+			want: &AccountDeletedPayload{AccountID: accountID, DeletedAt: at},
+		},
+		{
+			name:        "json created",
+			contentType: ContentTypeJSON,
+			data:        jsonOf(AccountCreatedPayload{AccountID: accountID, Username: "alice", CreatedAt: at}),
+			into:        func() any { return &AccountCreatedPayload{} },
+			want:        &AccountCreatedPayload{AccountID: accountID, Username: "alice", CreatedAt: at},
+		},
+		{
+			name:        "json with leading whitespace detected",
+			contentType: "",
+			data:        append([]byte("\n\t "), jsonOf(AccountDeletedPayload{AccountID: accountID, DeletedAt: at})...),
+			into:        func() any { return &AccountDeletedPayload{} },
+			want:        &AccountDeletedPayload{AccountID: accountID, DeletedAt: at},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.into()
+			if err := Decode(tt.contentType, tt.data, got); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Decode() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		data        []byte
+	}{
+		{name: "unsupported content type", contentType: "text/plain", data: []byte("{}")},
+		{name: "malformed content type", contentType: "application/", data: []byte("{}")},
+		{name: "truncated protobuf", contentType: ContentTypeProtobuf, data: []byte{0x0a, 0x10, 0x01}},
+		{name: "wrong wire type", contentType: ContentTypeProtobuf, data: []byte{0x08, 0x01}},
+		{name: "protobuf as json", contentType: ContentTypeJSON, data: []byte{0x0a, 0x01, 0x61}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got AccountCreatedPayload
+			if err := Decode(tt.contentType, tt.data, &got); err == nil {
+				t.Fatalf("Decode() error = nil, decoded %+v", got)
+			}
+		})
+	}
+}
+
+func TestDetectContentType(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{name: "empty", data: nil, want: ContentTypeJSON},
+		{name: "json object", data: []byte(`{"account_id":"x"}`), want: ContentTypeJSON},
+		{name: "json after newline", data: []byte("\n{}"), want: ContentTypeJSON},
+		{name: "protobuf string field", data: []byte{0x0a, 0x03, 'a', 'b', 'c'}, want: ContentTypeProtobuf},
+		{name: "protobuf brace length", data: append([]byte{0x0a, '{'}, strings.Repeat("a", '{')...), want: ContentTypeProtobuf},
+		{name: "protobuf varint field", data: []byte{0x08, 0x01}, want: ContentTypeProtobuf},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectContentType(tt.data); got != tt.want {
+				t.Errorf("DetectContentType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package contracts
+
+func (p ProfileUpdatedPayload) MarshalProto() ([]byte, error) {
+	var b []byte
+	b = appendProtoUUID(b, 1, p.AccountID)
+	b = appendProtoString(b, 2, p.Username)
+	b = appendProtoBool(b, 3, p.Official)
+	b = appendProtoOptionalString(b, 4, p.Pseudonym)
+	b = appendProtoOptionalString(b, 5, p.Description)
+	b = appendProtoTime(b, 7, p.UpdatedAt)
+	return b, nil
+}
+
+func (p ProfileCreatedPayload) MarshalProto() ([]byte, error) {
+	var b []byte
+	b = appendProtoUUID(b, 1, p.AccountID)
+	b = appendProtoString(b, 2, p.Username)
+	b = appendProtoTime(b, 3, p.CreatedAt)
+	return b, nil
+}
+
+func (p ProfileDeletedPayload) MarshalProto() ([]byte, error) {
+	var b []byte
+	b = appendProtoUUID(b, 1, p.AccountID)
+	b = appendProtoTime(b, 2, p.DeletedAt)
+	return b, nil
+}
@@ -0,0 +1,30 @@
+package contracts
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProfilesSnapshotTopicV1 is a log-compacted topic keyed by account id with
+// the latest full state of every profile, new consumers bootstrap from it and
+// then follow profiles.v2. A deleted profile is a profile.snapshot.deleted marker,
+// the outbox can not hold a message without a value.
+const ProfilesSnapshotTopicV1 = "profiles.snapshot.v1"
+
+const (
+	ProfileSnapshotEvent        = "profile.snapshot"
+	ProfileSnapshotDeletedEvent = "profile.snapshot.deleted"
+)
+
+type ProfileSnapshotPayload struct {
+	Profile ProfileV2 `json:"profile"`
+}
+
+// ProfileSnapshotDeletedPayload replaces the state of a deleted profile,
+// version is one above the last version of the profile.
+type ProfileSnapshotDeletedPayload struct {
+	AccountID uuid.UUID `json:"account_id"`
+	Version   int64     `json:"version"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
@@ -0,0 +1,18 @@
+package contracts
+
+func (p ProfileSnapshotPayload) MarshalProto() ([]byte, error) {
+	profile, err := p.Profile.MarshalProto()
+	if err != nil {
+		return nil, err
+	}
+
+	return appendProtoMessage(nil, 1, profile), nil
+}
+
+func (p ProfileSnapshotDeletedPayload) MarshalProto() ([]byte, error) {
+	var b []byte
+	b = appendProtoUUID(b, 1, p.AccountID)
+	b = appendProtoInt64(b, 2, p.Version)
+	b = appendProtoTime(b, 3, p.DeletedAt)
+	return b, nil
+}
@@ -0,0 +1,91 @@
+package contracts
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProfilesTopicV2 carries the full profile state, a consumer can keep a local
+// replica from it alone. Events keep the names of profiles.v1.
+const ProfilesTopicV2 = "profiles.v2"
+
+// ProfileV2 is the full state of a profile. Version grows by one on every
+// change of the profile, an event with a version not above the one already
+// applied is stale and can be skipped. The versions of a profile created again
+// after a deletion continue above the version of profile.deleted.
+type ProfileV2 struct {
+	AccountID      uuid.UUID         `json:"account_id"`
+	Username       string            `json:"username"`
+	Official       bool              `json:"official"`
+	Pseudonym      *string           `json:"pseudonym,omitempty"`
+	Description    *string           `json:"description,omitempty"`
+	Avatar         *string           `json:"avatar,omitempty"`
+	AvatarVariants map[string]string `json:"avatar_variants,omitempty"`
+	Banner         *string           `json:"banner,omitempty"`
+	Version        int64             `json:"version"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ProfileCreatedPayloadV2 struct {
+	Profile ProfileV2 `json:"profile"`
+}
+
+type ProfileUpdatedPayloadV2 struct {
+	Profile       ProfileV2            `json:"profile"`
+	ChangedFields []ProfileFieldChange `json:"changed_fields"`
+}
+
+// ProfileFieldChange is the change of one profile field, a null value is an unset field.
+// Values are left out for the media fields, their stored keys are not published.
+type ProfileFieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+
+	// ValuesOmitted marks a change published by the field name alone.
+	ValuesOmitted bool `json:"-"`
+}
+
+func (c ProfileFieldChange) MarshalJSON() ([]byte, error) {
+	if c.ValuesOmitted {
+		return json.Marshal(struct {
+			Field string `json:"field"`
+		}{Field: c.Field})
+	}
+
+	type change ProfileFieldChange
+	return json.Marshal(change(c))
+}
+
+// Dedicated events are written next to profile.updated on profiles.v2 for the
+// fields consumers react to on their own. profiles.v1 does not carry them.
+const (
+	ProfileUsernameChangedEvent = "profile.username.changed"
+	ProfileOfficialChangedEvent = "profile.official.changed"
+	ProfileAvatarChangedEvent   = "profile.avatar.changed"
+	ProfileBannerChangedEvent   = "profile.banner.changed"
+)
+
+// ProfileFieldChangedPayload is the payload of the dedicated events. Old and New
+// are left out of the avatar and banner events, the new media is read from the
+// profile itself.
+type ProfileFieldChangedPayload struct {
+	AccountID uuid.UUID `json:"account_id"`
+	Old       any       `json:"old,omitempty"`
+	New       any       `json:"new,omitempty"`
+	Version   int64     `json:"version"`
+
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// ProfileDeletedPayloadV2 carries the version of the deletion, one above the
+// last version of the profile. A profile created again continues above it.
+type ProfileDeletedPayloadV2 struct {
+	AccountID uuid.UUID `json:"account_id"`
+	Version   int64     `json:"version"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
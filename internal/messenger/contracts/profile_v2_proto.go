@@ -0,0 +1,83 @@
+package contracts
+
+import "google.golang.org/protobuf/encoding/protowire"
+
+func (p ProfileV2) MarshalProto() ([]byte, error) {
+	var b []byte
+	b = appendProtoUUID(b, 1, p.AccountID)
+	b = appendProtoString(b, 2, p.Username)
+	b = appendProtoBool(b, 3, p.Official)
+	b = appendProtoOptionalString(b, 4, p.Pseudonym)
+	b = appendProtoOptionalString(b, 5, p.Description)
+	b = appendProtoOptionalString(b, 6, p.Avatar)
+	b = appendProtoStringMap(b, 7, p.AvatarVariants)
+	b = appendProtoOptionalString(b, 8, p.Banner)
+	b = appendProtoInt64(b, 9, p.Version)
+	b = appendProtoTime(b, 10, p.CreatedAt)
+	b = appendProtoTime(b, 11, p.UpdatedAt)
+	return b, nil
+}
+
+func (p ProfileCreatedPayloadV2) MarshalProto() ([]byte, error) {
+	profile, err := p.Profile.MarshalProto()
+	if err != nil {
+		return nil, err
+	}
+
+	return appendProtoMessage(nil, 1, profile), nil
+}
+
+func (p ProfileUpdatedPayloadV2) MarshalProto() ([]byte, error) {
+	profile, err := p.Profile.MarshalProto()
+	if err != nil {
+		return nil, err
+	}
+
+	b := appendProtoMessage(nil, 1, profile)
+	return appendProtoFieldChanges(b, 2, p.ChangedFields)
+}
+
+func (p ProfileDeletedPayloadV2) MarshalProto() ([]byte, error) {
+	var b []byte
+	b = appendProtoUUID(b, 1, p.AccountID)
+	b = appendProtoTime(b, 2, p.DeletedAt)
+	b = appendProtoInt64(b, 3, p.Version)
+	return b, nil
+}
+
+func appendProtoFieldChanges(b []byte, num protowire.Number, changes []ProfileFieldChange) ([]byte, error) {
+	for _, ch := range changes {
+		var (
+			msg []byte
+			err error
+		)
+		msg = appendProtoString(msg, 1, ch.Field)
+		if msg, err = appendProtoFieldValue(msg, 2, ch.Old); err != nil {
+			return nil, err
+		}
+		if msg, err = appendProtoFieldValue(msg, 3, ch.New); err != nil {
+			return nil, err
+		}
+
+		b = appendProtoMessage(b, num, msg)
+	}
+
+	return b, nil
+}
+
+func (p ProfileFieldChangedPayload) MarshalProto() ([]byte, error) {
+	var (
+		b   []byte
+		err error
+	)
+	b = appendProtoUUID(b, 1, p.AccountID)
+	if b, err = appendProtoFieldValue(b, 2, p.Old); err != nil {
+		return nil, err
+	}
+	if b, err = appendProtoFieldValue(b, 3, p.New); err != nil {
+		return nil, err
+	}
+	b = appendProtoInt64(b, 4, p.Version)
+	b = appendProtoTime(b, 5, p.ChangedAt)
+	return b, nil
+}
@@ -0,0 +1,63 @@
+package contracts
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestProfileChangesJSON(t *testing.T) {
+	accountID := uuid.MustParse("5f0c6f5e-2d4b-4d8e-9f57-0b8f4f2b1c11")
+	at := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		payload any
+		want    string
+	}{
+		{
+			name:    "value change",
+			payload: ProfileFieldChange{Field: "username", Old: "alice", New: "bob"},
+			want:    `{"field":"username","old":"alice","new":"bob"}`,
+		},
+		{
+			name:    "unset value",
+			payload: ProfileFieldChange{Field: "pseudonym", Old: "Alice"},
+			want:    `{"field":"pseudonym","old":"Alice","new":null}`,
+		},
+		{
+			name:    "media change",
+			payload: ProfileFieldChange{Field: "avatar", ValuesOmitted: true},
+			want:    `{"field":"avatar"}`,
+		},
+		{
+			name: "official changed event",
+			payload: ProfileFieldChangedPayload{
+				AccountID: accountID, Old: false, New: true, Version: 3, ChangedAt: at,
+			},
+			want: `{"account_id":"` + accountID.String() + `","old":false,"new":true,"version":3,` +
+				`"changed_at":"2026-10-18T12:00:00Z"}`,
+		},
+		{
+			name: "avatar changed event",
+			payload: ProfileFieldChangedPayload{
+				AccountID: accountID, Version: 4, ChangedAt: at,
+			},
+			want: `{"account_id":"` + accountID.String() + `","version":4,"changed_at":"2026-10-18T12:00:00Z"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.payload)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("json.Marshal() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package contracts
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The payloads are encoded with protowire by hand, field numbers and types
+// follow the .proto files next to them.
+
+func appendProtoString(b []byte, num protowire.Number, v string) []byte {
+	if v == "" {
+		return b
+	}
+
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, v)
+}
+
+// appendProtoOptionalString writes a set value even when it is empty, as proto3 optional does.
+func appendProtoOptionalString(b []byte, num protowire.Number, v *string) []byte {
+	if v == nil {
+		return b
+	}
+
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, *v)
+}
+
+func appendProtoBool(b []byte, num protowire.Number, v bool) []byte {
+	if !v {
+		return b
+	}
+
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, protowire.EncodeBool(v))
+}
+
+func appendProtoInt64(b []byte, num protowire.Number, v int64) []byte {
+	if v == 0 {
+		return b
+	}
+
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, uint64(v))
+}
+
+func appendProtoUUID(b []byte, num protowire.Number, v uuid.UUID) []byte {
+	if v == uuid.Nil {
+		return b
+	}
+
+	return appendProtoString(b, num, v.String())
+}
+
+func appendProtoMessage(b []byte, num protowire.Number, msg []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, msg)
+}
+
+// appendProtoTime writes a google.protobuf.Timestamp.
+func appendProtoTime(b []byte, num protowire.Number, v time.Time) []byte {
+	if v.IsZero() {
+		return b
+	}
+
+	var ts []byte
+	ts = appendProtoInt64(ts, 1, v.Unix())
+	ts = appendProtoInt64(ts, 2, int64(v.Nanosecond()))
+	return appendProtoMessage(b, num, ts)
+}
+
+// appendProtoStringMap writes a map<string, string> with sorted keys, so that equal maps encode equally.
+func appendProtoStringMap(b []byte, num protowire.Number, m map[string]string) []byte {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		var entry []byte
+		entry = appendProtoString(entry, 1, k)
+		entry = appendProtoString(entry, 2, m[k])
+		b = appendProtoMessage(b, num, entry)
+	}
+
+	return b
+}
+
+// appendProtoFieldValue writes a ProfileFieldValue, nothing for a nil value.
+func appendProtoFieldValue(b []byte, num protowire.Number, v any) ([]byte, error) {
+	var msg []byte
+	switch v := v.(type) {
+	case nil:
+		return b, nil
+	case string:
+		msg = protowire.AppendTag(msg, 1, protowire.BytesType)
+		msg = protowire.AppendString(msg, v)
+	case bool:
+		msg = protowire.AppendTag(msg, 2, protowire.VarintType)
+		msg = protowire.AppendVarint(msg, protowire.EncodeBool(v))
+	default:
+		return nil, fmt.Errorf("profile field value of type %T has no protobuf encoding", v)
+	}
+
+	return appendProtoMessage(b, num, msg), nil
+}
+
+// protoField is a field read from a message, varint holds a varint value
+// and bytes a length delimited one.
+type protoField struct {
+	num    protowire.Number
+	typ    protowire.Type
+	varint uint64
+	bytes  []byte
+}
+
+// rangeProtoFields calls fn for every field of the message, unknown fields are up to fn to skip.
+func rangeProtoFields(data []byte, fn func(f protoField) error) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		f := protoField{num: num, typ: typ}
+		switch typ {
+		case protowire.VarintType:
+			f.varint, n = protowire.ConsumeVarint(data)
+		case protowire.BytesType:
+			f.bytes, n = protowire.ConsumeBytes(data)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, data)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f protoField) expect(typ protowire.Type) error {
+	if f.typ != typ {
+		return fmt.Errorf("protobuf field %d has wire type %d, expected %d", f.num, f.typ, typ)
+	}
+
+	return nil
+}
+
+func (f protoField) string() (string, error) {
+	if err := f.expect(protowire.BytesType); err != nil {
+		return "", err
+	}
+
+	return string(f.bytes), nil
+}
+
+func (f protoField) uuid() (uuid.UUID, error) {
+	s, err := f.string()
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("protobuf field %d: %w", f.num, err)
+	}
+
+	return id, nil
+}
+
+func (f protoField) time() (time.Time, error) {
+	if err := f.expect(protowire.BytesType); err != nil {
+		return time.Time{}, err
+	}
+
+	var seconds, nanos int64
+	err := rangeProtoFields(f.bytes, func(ts protoField) error {
+		if err := ts.expect(protowire.VarintType); err != nil {
+			return err
+		}
+
+		switch ts.num {
+		case 1:
+			seconds = int64(ts.varint)
+		case 2:
+			nanos = int64(ts.varint)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("protobuf timestamp field %d: %w", f.num, err)
+	}
+
+	return time.Unix(seconds, nanos).UTC(), nil
+}
@@ -0,0 +1,580 @@
+package contracts
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// protoFiles are the schemas of the payloads, compiled by protoSchemas.
+var protoFiles = []string{"account.proto", "account_v2.proto", "profile.proto", "profile_v2.proto", "profile_snapshot.proto"}
+
+// protoSchemas compiles the .proto files next to the payloads, so that the hand written
+// encoders are checked against the schemas consumers generate their code from.
+func protoSchemas(t *testing.T) *protoregistry.Files {
+	t.Helper()
+
+	set := &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{
+			protodesc.ToFileDescriptorProto(timestamppb.File_google_protobuf_timestamp_proto),
+		},
+	}
+	for _, name := range protoFiles {
+		src, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		fd, err := parseProtoFile(name, string(src))
+		if err != nil {
+			t.Fatalf("parsing %s: %v", name, err)
+		}
+		set.File = append(set.File, fd)
+	}
+
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		t.Fatalf("compiling the proto files: %v", err)
+	}
+
+	return files
+}
+
+var protoScalarTypes = map[string]descriptorpb.FieldDescriptorProto_Type{
+	"string": descriptorpb.FieldDescriptorProto_TYPE_STRING,
+	"bool":   descriptorpb.FieldDescriptorProto_TYPE_BOOL,
+	"int64":  descriptorpb.FieldDescriptorProto_TYPE_INT64,
+}
+
+// protoParser reads the subset of proto3 the contracts use: messages with scalar,
+// message, optional, repeated and map<string, string> fields, oneofs and reserved numbers.
+type protoParser struct {
+	tokens []string
+	pkg    string
+}
+
+func parseProtoFile(name, src string) (*descriptorpb.FileDescriptorProto, error) {
+	p := &protoParser{tokens: tokenizeProto(src)}
+	fd := &descriptorpb.FileDescriptorProto{Name: proto.String(name)}
+
+	for len(p.tokens) > 0 {
+		switch tok := p.next(); tok {
+		case "syntax":
+			if err := p.expect("="); err != nil {
+				return nil, err
+			}
+			fd.Syntax = proto.String(unquote(p.next()))
+		case "package":
+			p.pkg = p.next()
+			fd.Package = proto.String(p.pkg)
+		case "import":
+			fd.Dependency = append(fd.Dependency, unquote(p.next()))
+		case "message":
+			msg, err := p.message()
+			if err != nil {
+				return nil, err
+			}
+			fd.MessageType = append(fd.MessageType, msg)
+			continue
+		default:
+			return nil, fmt.Errorf("unexpected %q", tok)
+		}
+
+		if err := p.expect(";"); err != nil {
+			return nil, err
+		}
+	}
+
+	return fd, nil
+}
+
+func (p *protoParser) message() (*descriptorpb.DescriptorProto, error) {
+	msg := &descriptorpb.DescriptorProto{Name: proto.String(p.next())}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	var optional []*descriptorpb.FieldDescriptorProto
+	for {
+		switch tok := p.next(); tok {
+		case "}":
+			// synthetic oneofs of optional fields go after the real ones
+			for _, f := range optional {
+				f.OneofIndex = proto.Int32(int32(len(msg.OneofDecl)))
+				msg.OneofDecl = append(msg.OneofDecl, &descriptorpb.OneofDescriptorProto{Name: proto.String("_" + f.GetName())})
+			}
+			return msg, nil
+		case "reserved":
+			for {
+				num, err := strconv.Atoi(p.next())
+				if err != nil {
+					return nil, err
+				}
+				msg.ReservedRange = append(msg.ReservedRange, &descriptorpb.DescriptorProto_ReservedRange{
+					Start: proto.Int32(int32(num)),
+					End:   proto.Int32(int32(num + 1)),
+				})
+				if p.next() == ";" {
+					break
+				}
+			}
+		case "oneof":
+			index := proto.Int32(int32(len(msg.OneofDecl)))
+			msg.OneofDecl = append(msg.OneofDecl, &descriptorpb.OneofDescriptorProto{Name: proto.String(p.next())})
+			if err := p.expect("{"); err != nil {
+				return nil, err
+			}
+			for p.peek() != "}" {
+				f, err := p.field(p.next())
+				if err != nil {
+					return nil, err
+				}
+				f.OneofIndex = index
+				msg.Field = append(msg.Field, f)
+			}
+			p.next()
+		case "map":
+			f, entry, err := p.mapField(msg.GetName())
+			if err != nil {
+				return nil, err
+			}
+			msg.Field = append(msg.Field, f)
+			msg.NestedType = append(msg.NestedType, entry)
+		case "optional", "repeated":
+			f, err := p.field(p.next())
+			if err != nil {
+				return nil, err
+			}
+			if tok == "optional" {
+				f.Proto3Optional = proto.Bool(true)
+				optional = append(optional, f)
+			} else {
+				f.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+			}
+			msg.Field = append(msg.Field, f)
+		default:
+			f, err := p.field(tok)
+			if err != nil {
+				return nil, err
+			}
+			msg.Field = append(msg.Field, f)
+		}
+	}
+}
+
+// field reads "name = number;" of a field of the type.
+func (p *protoParser) field(typ string) (*descriptorpb.FieldDescriptorProto, error) {
+	name := p.next()
+	if err := p.expect("="); err != nil {
+		return nil, err
+	}
+	num, err := strconv.Atoi(p.next())
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(";"); err != nil {
+		return nil, err
+	}
+
+	f := &descriptorpb.FieldDescriptorProto{
+		Name:   proto.String(name),
+		Number: proto.Int32(int32(num)),
+		Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+	}
+	if scalar, ok := protoScalarTypes[typ]; ok {
+		f.Type = scalar.Enum()
+		return f, nil
+	}
+
+	f.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+	if strings.Contains(typ, ".") {
+		f.TypeName = proto.String("." + typ)
+	} else {
+		f.TypeName = proto.String("." + p.pkg + "." + typ)
+	}
+
+	return f, nil
+}
+
+// mapField reads "<key, value> name = number;" into a repeated field of a map entry message.
+func (p *protoParser) mapField(parent string) (*descriptorpb.FieldDescriptorProto, *descriptorpb.DescriptorProto, error) {
+	if err := p.expect("<"); err != nil {
+		return nil, nil, err
+	}
+	key := p.next()
+	if err := p.expect(","); err != nil {
+		return nil, nil, err
+	}
+	value := p.next()
+	if err := p.expect(">"); err != nil {
+		return nil, nil, err
+	}
+
+	f, err := p.field("")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyField, err := (&protoParser{tokens: []string{"key", "=", "1", ";"}, pkg: p.pkg}).field(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	valueField, err := (&protoParser{tokens: []string{"value", "=", "2", ";"}, pkg: p.pkg}).field(value)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the entry message is named after the field in camel case, as protoc names it
+	entryName := ""
+	for _, part := range strings.Split(f.GetName(), "_") {
+		if part != "" {
+			entryName += strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	entryName += "Entry"
+	entry := &descriptorpb.DescriptorProto{
+		Name:    proto.String(entryName),
+		Field:   []*descriptorpb.FieldDescriptorProto{keyField, valueField},
+		Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+	}
+
+	f.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	f.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+	f.TypeName = proto.String("." + p.pkg + "." + parent + "." + entryName)
+
+	return f, entry, nil
+}
+
+func (p *protoParser) next() string {
+	if len(p.tokens) == 0 {
+		return ""
+	}
+
+	tok := p.tokens[0]
+	p.tokens = p.tokens[1:]
+	return tok
+}
+
+func (p *protoParser) peek() string {
+	if len(p.tokens) == 0 {
+		return ""
+	}
+
+	return p.tokens[0]
+}
+
+func (p *protoParser) expect(tok string) error {
+	if got := p.next(); got != tok {
+		return fmt.Errorf("expected %q, got %q", tok, got)
+	}
+
+	return nil
+}
+
+// tokenizeProto splits the source into identifiers, numbers, quoted strings and
+// punctuation, comments are dropped.
+func tokenizeProto(src string) []string {
+	var tokens []string
+	for len(src) > 0 {
+		r := rune(src[0])
+		switch {
+		case strings.HasPrefix(src, "//"):
+			end := strings.IndexByte(src, '\n')
+			if end < 0 {
+				end = len(src)
+			}
+			src = src[end:]
+		case unicode.IsSpace(r):
+			src = src[1:]
+		case r == '"':
+			end := strings.IndexByte(src[1:], '"') + 2
+			tokens = append(tokens, src[:end])
+			src = src[end:]
+		case r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			end := strings.IndexFunc(src, func(r rune) bool {
+				return r != '_' && r != '.' && !unicode.IsLetter(r) && !unicode.IsDigit(r)
+			})
+			if end < 0 {
+				end = len(src)
+			}
+			tokens = append(tokens, src[:end])
+			src = src[end:]
+		default:
+			tokens = append(tokens, src[:1])
+			src = src[1:]
+		}
+	}
+
+	return tokens
+}
+
+func unquote(s string) string {
+	return strings.Trim(s, `"`)
+}
+
+// checkNoUnknownFields fails if a field of the message or of a nested one was not in
+// the schema, a field written with the wrong wire type is kept as unknown as well.
+func checkNoUnknownFields(t *testing.T, m protoreflect.Message) {
+	t.Helper()
+
+	if len(m.GetUnknown()) > 0 {
+		t.Fatalf("%s has unknown fields", m.Descriptor().FullName())
+	}
+
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsMap():
+		case fd.IsList() && fd.Message() != nil:
+			for i := 0; i < v.List().Len(); i++ {
+				checkNoUnknownFields(t, v.List().Get(i).Message())
+			}
+		case fd.Message() != nil:
+			checkNoUnknownFields(t, v.Message())
+		}
+		return true
+	})
+}
+
+func TestMarshalProtoMatchesSchema(t *testing.T) {
+	files := protoSchemas(t)
+
+	accountID := uuid.MustParse("5f0c6f5e-2d4b-4d8e-9f57-0b8f4f2b1c11")
+	at := time.Date(2026, 10, 18, 12, 30, 15, 123000000, time.UTC)
+	empty := ""
+	bio := "about me"
+	avatar := "profile/avatar/5f0c6f5e/a.png"
+
+	profile := ProfileV2{
+		AccountID:      accountID,
+		Username:       "alice",
+		Official:       true,
+		Pseudonym:      &empty,
+		Description:    &bio,
+		Avatar:         &avatar,
+		AvatarVariants: map[string]string{"small": "s.png", "large": "l.png"},
+		Version:        3,
+		CreatedAt:      at,
+		UpdatedAt:      at,
+	}
+	profileJSON := `{
+		"account_id": "5f0c6f5e-2d4b-4d8e-9f57-0b8f4f2b1c11",
+		"username": "alice",
+		"official": true,
+		"pseudonym": "",
+		"description": "about me",
+		"avatar": "profile/avatar/5f0c6f5e/a.png",
+		"avatar_variants": {"small": "s.png", "large": "l.png"},
+		"version": "3",
+		"created_at": "2026-10-18T12:30:15.123Z",
+		"updated_at": "2026-10-18T12:30:15.123Z"
+	}`
+
+	// want is the payload as protojson with the field names of the schema
+	tests := []struct {
+		name    string
+		message protoreflect.FullName
+		payload ProtoMarshaler
+		want    string
+	}{
+		{
+			name:    "v1 profile updated",
+			message: "netbill.profiles.v1.ProfileUpdatedPayload",
+			payload: ProfileUpdatedPayload{AccountID: accountID, Username: "alice", Official: true, Pseudonym: &empty, UpdatedAt: at},
+			want: `{"account_id": "5f0c6f5e-2d4b-4d8e-9f57-0b8f4f2b1c11", "username": "alice", "official": true,
+				"pseudonym": "", "updated_at": "2026-10-18T12:30:15.123Z"}`,
+		},
+		{
+			name:    "v1 profile created",
+			message: "netbill.profiles.v1.ProfileCreatedPayload",
+			payload: ProfileCreatedPayload{AccountID: accountID, Username: "alice", CreatedAt: at},
+			want: `{"account_id": "5f0c6f5e-2d4b-4d8e-9f57-0b8f4f2b1c11", "username": "alice",
+				"created_at": "2026-10-18T12:30:15.123Z"}`,
+		},
+		{
+			name:    "v1 profile deleted",
+			message: "netbill.profiles.v1.ProfileDeletedPayload",
+			payload: ProfileDeletedPayload{AccountID: accountID, DeletedAt: at},
+			want:    `{"account_id": "5f0c6f5e-2d4b-4d8e-9f57-0b8f4f2b1c11", "deleted_at": "2026-10-18T12:30:15.123Z"}`,
+		},
+		{
+			name:    "v2 profile created",
+			message: "netbill.profiles.v2.ProfileCreatedPayload",
+			payload: ProfileCreatedPayloadV2{Profile: profile},
+			want:    `{"profile": ` + profileJSON + `}`,
+		},
+		{
+			name:    "v2 profile updated",
+			message: "netbill.profiles.v2.ProfileUpdatedPayload",
+			payload: ProfileUpdatedPayloadV2{
+				Profile: ProfileV2{AccountID: accountID, Username: "bob"},
+				ChangedFields: []ProfileFieldChange{
+					{Field: "username", Old: "alice", New: "bob"},
+					{Field: "official", Old: false, New: true},
+					{Field: "pseudonym", Old: nil, New: "Bob"},
+					{Field: "avatar", ValuesOmitted: true},
+				},
+			},
+			want: `{
+				"profile": {"account_id": "5f0c6f5e-2d4b-4d8e-9f57-0b8f4f2b1c11", "username": "bob"},
+				"changed_fields": [
+					{"field": "username", "old": {"string_value": "alice"}, "new": {"string_value": "bob"}},
+					{"field": "official", "old": {"bool_value": false}, "new": {"bool_value": true}},
+					{"field": "pseudonym", "new": {"string_value": "Bob"}},
+					{"field": "avatar"}
+				]
+			}`,
+		},
+		{
+			name:    "v2 field changed",
+			message: "netbill.profiles.v2.ProfileFieldChangedPayload",
+			payload: ProfileFieldChangedPayload{AccountID: accountID, Old: "alice", New: "bob", Version: 4, ChangedAt: at},
+			want: `{"account_id": "5f0c6f5e-2d4b-4d8e-9f57-0b8f4f2b1c11", "old": {"string_value": "alice"},
+				"new": {"string_value": "bob"}, "version": "4", "changed_at": "2026-10-18T12:30:15.123Z"}`,
+		},
+		{
+			name:    "v2 profile deleted",
+			message: "netbill.profiles.v2.ProfileDeletedPayload",
+			payload: ProfileDeletedPayloadV2{AccountID: accountID, Version: 5, DeletedAt: at},
+			want: `{"account_id": "5f0c6f5e-2d4b-4d8e-9f57-0b8f4f2b1c11", "deleted_at": "2026-10-18T12:30:15.123Z",
+				"version": "5"}`,
+		},
+		{
+			name:    "snapshot",
+			message: "netbill.profiles.snapshot.v1.ProfileSnapshotPayload",
+			payload: ProfileSnapshotPayload{Profile: profile},
+			want:    `{"profile": ` + profileJSON + `}`,
+		},
+		{
+			name:    "snapshot deleted",
+			message: "netbill.profiles.snapshot.v1.ProfileSnapshotDeletedPayload",
+			payload: ProfileSnapshotDeletedPayload{AccountID: accountID, Version: 5, DeletedAt: at},
+			want: `{"account_id": "5f0c6f5e-2d4b-4d8e-9f57-0b8f4f2b1c11", "version": "5",
+				"deleted_at": "2026-10-18T12:30:15.123Z"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desc, err := files.FindDescriptorByName(tt.message)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := tt.payload.MarshalProto()
+			if err != nil {
+				t.Fatalf("MarshalProto() error = %v", err)
+			}
+
+			msg := dynamicpb.NewMessage(desc.(protoreflect.MessageDescriptor))
+			if err := proto.Unmarshal(data, msg); err != nil {
+				t.Fatalf("proto.Unmarshal() error = %v", err)
+			}
+			checkNoUnknownFields(t, msg)
+
+			got, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var gotValue, wantValue any
+			if err := json.Unmarshal(got, &gotValue); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tt.want), &wantValue); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(gotValue, wantValue) {
+				t.Fatalf("decoded payload = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalProtoMatchesSchema(t *testing.T) {
+	files := protoSchemas(t)
+
+	accountID := uuid.MustParse("5f0c6f5e-2d4b-4d8e-9f57-0b8f4f2b1c11")
+	at := time.Date(2026, 10, 18, 12, 30, 15, 123000000, time.UTC)
+
+	// payload is the message as protojson, encoded with the schema for the decoder
+	tests := []struct {
+		name    string
+		message protoreflect.FullName
+		payload string
+		into    ProtoUnmarshaler
+		want    ProtoUnmarshaler
+	}{
+		{
+			name:    "account created",
+			message: "netbill.accounts.v1.AccountCreatedPayload",
+			payload: `{"account_id": "5f0c6f5e-2d4b-4d8e-9f57-0b8f4f2b1c11", "username": "alice",
+				"created_at": "2026-10-18T12:30:15.123Z"}`,
+			into: &AccountCreatedPayload{},
+			want: &AccountCreatedPayload{AccountID: accountID, Username: "alice", CreatedAt: at},
+		},
+		{
+			name:    "account username updated",
+			message: "netbill.accounts.v1.AccountUsernameUpdatedPayload",
+			payload: `{"account_id": "5f0c6f5e-2d4b-4d8e-9f57-0b8f4f2b1c11", "new_username": "bob",
+				"updated_at": "2026-10-18T12:30:15.123Z"}`,
+			into: &AccountUsernameUpdatedPayload{},
+			want: &AccountUsernameUpdatedPayload{AccountID: accountID, NewUsername: "bob", UpdatedAt: at},
+		},
+		{
+			name:    "account username updated v2",
+			message: "netbill.accounts.v2.AccountUsernameUpdatedPayload",
+			payload: `{"account_id": "5f0c6f5e-2d4b-4d8e-9f57-0b8f4f2b1c11", "username": "bob",
+				"old_username": "alice", "updated_at": "2026-10-18T12:30:15.123Z"}`,
+			into: &AccountUsernameUpdatedPayloadV2{},
+			want: &AccountUsernameUpdatedPayloadV2{AccountID: accountID, Username: "bob", OldUsername: "alice", UpdatedAt: at},
+		},
+		{
+			name:    "account deleted",
+			message: "netbill.accounts.v1.AccountDeletedPayload",
+			payload: `{"account_id": "5f0c6f5e-2d4b-4d8e-9f57-0b8f4f2b1c11", "deleted_at": "2026-10-18T12:30:15.123Z"}`,
+			into:    &AccountDeletedPayload{},
+			want:    &AccountDeletedPayload{AccountID: accountID, DeletedAt: at},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desc, err := files.FindDescriptorByName(tt.message)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			msg := dynamicpb.NewMessage(desc.(protoreflect.MessageDescriptor))
+			if err := protojson.Unmarshal([]byte(tt.payload), msg); err != nil {
+				t.Fatalf("protojson.Unmarshal() error = %v", err)
+			}
+			data, err := proto.Marshal(msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := tt.into.UnmarshalProto(data); err != nil {
+				t.Fatalf("UnmarshalProto() error = %v", err)
+			}
+			if !reflect.DeepEqual(tt.into, tt.want) {
+				t.Fatalf("UnmarshalProto() = %+v, want %+v", tt.into, tt.want)
+			}
+		})
+	}
+}
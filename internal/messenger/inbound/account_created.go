@@ -2,7 +2,6 @@ package inbound
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 
 	"github.com/netbill/ape"
@@ -14,17 +13,21 @@ func (i *Inbound) AccountCreated(
 	ctx context.Context,
 	event inbox.Event,
 ) inbox.EventStatus {
-	var payload contracts.AccountCreatedPayload
-	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+	payload, err := decodeEvent[contracts.AccountCreatedPayload](i.versions, event)
+	switch {
+	case errors.Is(err, ErrUnknownEventVersion):
+		i.log.Errorf("unsupported event version, key %s, id: %s, error: %v", event.Key, event.ID, err)
+		return i.failed(ctx, event, err)
+	case err != nil:
 		i.log.Errorf("bad payload for %s, key %s, id: %s, error: %v", event.Type, event.Key, event.ID, err)
-		return inbox.EventStatusFailed
+		return i.failed(ctx, event, err)
 	}
 
 	if _, err := i.domain.CreateProfile(ctx, payload.AccountID, payload.Username); err != nil {
 		var ae *ape.Error
 		if errors.As(err, &ae) {
 			i.log.Errorf("failed to create profile, key %s, id: %s, error: %v", event.Key, event.ID, err)
-			return inbox.EventStatusFailed
+			return i.failed(ctx, event, err)
 		}
 
 		i.log.Errorf("failed to create profile due to internal error, key %s, id: %s, error: %v", event.Key, event.ID, err)
@@ -2,7 +2,6 @@ package inbound
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 
 	"github.com/netbill/ape"
@@ -14,13 +13,17 @@ func (i *Inbound) AccountUsernameUpdated(
 	ctx context.Context,
 	event inbox.Event,
 ) inbox.EventStatus {
-	var payload contracts.AccountUsernameUpdatedPayload
-	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+	payload, err := decodeEvent[contracts.AccountUsernameUpdatedPayloadV2](i.versions, event)
+	switch {
+	case errors.Is(err, ErrUnknownEventVersion):
+		i.log.Errorf("unsupported event version, key %s, id: %s, error: %v", event.Key, event.ID, err)
+		return i.failed(ctx, event, err)
+	case err != nil:
 		i.log.Errorf("bad payload for %s, key %s, id: %s, error: %v", event.Type, event.Key, event.ID, err)
-		return inbox.EventStatusFailed
+		return i.failed(ctx, event, err)
 	}
 
-	if _, err := i.domain.UpdateProfileUsername(ctx, payload.AccountID, payload.NewUsername); err != nil {
+	if _, err := i.domain.UpdateProfileUsername(ctx, payload.AccountID, payload.Username); err != nil {
 		var ae *ape.Error
 		if errors.As(err, &ae) {
 			i.log.Errorf(
@@ -31,7 +34,7 @@ func (i *Inbound) AccountUsernameUpdated(
 		}
 
 		i.log.Errorf("failed to update username, key %s, id: %s, error: %v", event.Key, event.ID, err)
-		return inbox.EventStatusFailed
+		return i.failed(ctx, event, err)
 	}
 
 	return inbox.EventStatusProcessed
@@ -4,22 +4,44 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/netbill/evebox/box/inbox"
 	"github.com/netbill/logium"
 	"github.com/netbill/profiles-svc/internal/core/models"
 )
 
 type Inbound struct {
-	log    *logium.Logger
-	domain domain
+	log      *logium.Logger
+	domain   domain
+	failures failures
+	versions *Versions
 }
 
-func New(log *logium.Logger, domain domain) *Inbound {
+// New creates the handlers, versions are the event versions they accept.
+func New(log *logium.Logger, domain domain, failures failures, versions *Versions) *Inbound {
 	return &Inbound{
-		log:    log,
-		domain: domain,
+		log:      log,
+		domain:   domain,
+		failures: failures,
+		versions: versions,
 	}
 }
 
+// failures keeps why an inbox event was marked failed, the inbox keeps only its status.
+type failures interface {
+	RecordInboxEventFailure(ctx context.Context, eventID uuid.UUID, reason string) error
+}
+
+// failed stores the reason and marks the event failed, the event stays pending
+// when the reason can not be stored so that it is not lost.
+func (i *Inbound) failed(ctx context.Context, event inbox.Event, reason error) inbox.EventStatus {
+	if err := i.failures.RecordInboxEventFailure(ctx, event.ID, reason.Error()); err != nil {
+		i.log.Errorf("failed to store failure reason, key %s, id: %s, error: %v", event.Key, event.ID, err)
+		return inbox.EventStatusPending
+	}
+
+	return inbox.EventStatusFailed
+}
+
 type domain interface {
 	CreateProfile(ctx context.Context, userID uuid.UUID, username string) (models.Profile, error)
 	UpdateProfileUsername(ctx context.Context, accountID uuid.UUID, username string) (models.Profile, error)
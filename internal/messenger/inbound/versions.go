@@ -0,0 +1,129 @@
+package inbound
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/netbill/evebox/box/inbox"
+	"github.com/netbill/profiles-svc/internal/messenger/contracts"
+)
+
+var ErrUnknownEventVersion = errors.New("unknown event version")
+
+type eventVersion struct {
+	eventType string
+	version   int32
+}
+
+// upcaster decodes the payload of one event version into the payload type
+// its handler works with, a payload of another version is converted on the way.
+type upcaster func(contentType string, payload []byte) (any, error)
+
+// Versions is the registry of the event versions the handlers accept.
+type Versions struct {
+	upcasters map[eventVersion]upcaster
+}
+
+func NewVersions() *Versions {
+	return &Versions{upcasters: make(map[eventVersion]upcaster)}
+}
+
+// Register adds the decoder of a version of an event type, T has to be the
+// payload type the event handler decodes with decodeEvent.
+func Register[T any](
+	v *Versions,
+	eventType string,
+	version int32,
+	decode func(contentType string, payload []byte) (T, error),
+) {
+	v.upcasters[eventVersion{eventType: eventType, version: version}] = func(
+		contentType string,
+		payload []byte,
+	) (any, error) {
+		return decode(contentType, payload)
+	}
+}
+
+// supported lists the registered versions of the event type.
+func (v *Versions) supported(eventType string) []int32 {
+	res := make([]int32, 0)
+	for k := range v.upcasters {
+		if k.eventType == eventType {
+			res = append(res, k.version)
+		}
+	}
+	slices.Sort(res)
+
+	return res
+}
+
+// decodeEvent decodes the event payload with the decoder of its type and version.
+// The evebox inbox keeps neither the message headers nor binary payloads, so the
+// content type is detected from the payload until evebox carries the header.
+func decodeEvent[T any](v *Versions, event inbox.Event) (T, error) {
+	var zero T
+
+	up, ok := v.upcasters[eventVersion{eventType: event.Type, version: event.Version}]
+	if !ok {
+		supported := v.supported(event.Type)
+		versions := make([]string, len(supported))
+		for i, s := range supported {
+			versions[i] = strconv.Itoa(int(s))
+		}
+
+		return zero, fmt.Errorf(
+			"%w: %s version %d, supported versions: [%s]",
+			ErrUnknownEventVersion, event.Type, event.Version, strings.Join(versions, ", "),
+		)
+	}
+
+	payload, err := up("", event.Payload)
+	if err != nil {
+		return zero, err
+	}
+
+	res, ok := payload.(T)
+	if !ok {
+		return zero, fmt.Errorf("%s version %d decodes to %T, handler expects %T", event.Type, event.Version, payload, zero)
+	}
+
+	return res, nil
+}
+
+// decodePayload decodes the payload as is with the codec of its content type,
+// a payload without one is detected by contracts.Decode.
+func decodePayload[T any](contentType string, payload []byte) (T, error) {
+	var res T
+	err := contracts.Decode(contentType, payload, &res)
+	return res, err
+}
+
+// DefaultVersions are the account event versions the service understands.
+func DefaultVersions() *Versions {
+	v := NewVersions()
+
+	Register(v, contracts.AccountCreatedEvent, 1, decodePayload[contracts.AccountCreatedPayload])
+	Register(v, contracts.AccountDeletedEvent, 1, decodePayload[contracts.AccountDeletedPayload])
+	Register(v, contracts.AccountUsernameUpdatedEvent, 1, upcastAccountUsernameUpdatedV1)
+	Register(v, contracts.AccountUsernameUpdatedEvent, 2, decodePayload[contracts.AccountUsernameUpdatedPayloadV2])
+
+	return v
+}
+
+// upcastAccountUsernameUpdatedV1 converts version 1 to version 2, the previous
+// username is unknown in version 1 and stays empty.
+func upcastAccountUsernameUpdatedV1(contentType string, payload []byte) (contracts.AccountUsernameUpdatedPayloadV2, error) {
+	p, err := decodePayload[contracts.AccountUsernameUpdatedPayload](contentType, payload)
+	if err != nil {
+		return contracts.AccountUsernameUpdatedPayloadV2{}, err
+	}
+
+	return contracts.AccountUsernameUpdatedPayloadV2{
+		AccountID: p.AccountID,
+		Username:  p.NewUsername,
+		UpdatedAt: p.UpdatedAt,
+	}, nil
+}
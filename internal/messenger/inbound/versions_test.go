@@ -0,0 +1,233 @@
+package inbound
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/netbill/evebox/box/inbox"
+	"github.com/netbill/logium"
+	"github.com/netbill/profiles-svc/internal/core/models"
+	"github.com/netbill/profiles-svc/internal/messenger/contracts"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+type fakeDomain struct {
+	domain
+
+	err     error
+	created []string
+	updated []string
+}
+
+func (d *fakeDomain) CreateProfile(_ context.Context, _ uuid.UUID, username string) (models.Profile, error) {
+	d.created = append(d.created, username)
+	return models.Profile{}, d.err
+}
+
+func (d *fakeDomain) UpdateProfileUsername(_ context.Context, _ uuid.UUID, username string) (models.Profile, error) {
+	d.updated = append(d.updated, username)
+	return models.Profile{}, d.err
+}
+
+// fakeFailures records the failure reasons by event.
+type fakeFailures struct {
+	err     error
+	reasons map[uuid.UUID]string
+}
+
+func (f *fakeFailures) RecordInboxEventFailure(_ context.Context, eventID uuid.UUID, reason string) error {
+	if f.err != nil {
+		return f.err
+	}
+
+	f.reasons[eventID] = reason
+	return nil
+}
+
+func TestAccountCreatedVersionDispatch(t *testing.T) {
+	accountID := uuid.New()
+	at := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	v1 := contracts.AccountCreatedPayload{AccountID: accountID, Username: "alice", CreatedAt: at}
+	v1JSON, err := json.Marshal(v1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// account payloads are only consumed, the protobuf message is written by hand
+	v1Proto := protowire.AppendTag(nil, 1, protowire.BytesType)
+	v1Proto = protowire.AppendString(v1Proto, accountID.String())
+	v1Proto = protowire.AppendTag(v1Proto, 2, protowire.BytesType)
+	v1Proto = protowire.AppendString(v1Proto, "alice")
+
+	tests := []struct {
+		name        string
+		version     int32
+		payload     []byte
+		domainErr   error
+		failuresErr error
+		wantStatus  inbox.EventStatus
+		wantCreated []string
+		wantReason  string
+	}{
+		{
+			name:        "v1 json",
+			version:     1,
+			payload:     v1JSON,
+			wantStatus:  inbox.EventStatusProcessed,
+			wantCreated: []string{"alice"},
+		},
+		{
+			name:        "v1 protobuf is detected",
+			version:     1,
+			payload:     v1Proto,
+			wantStatus:  inbox.EventStatusProcessed,
+			wantCreated: []string{"alice"},
+		},
+		{
+			name:       "unknown version is not decoded as v1",
+			version:    2,
+			payload:    v1JSON,
+			wantStatus: inbox.EventStatusFailed,
+			wantReason: "unknown event version: account.created version 2, supported versions: [1]",
+		},
+		{
+			name:        "failure reason can not be stored",
+			version:     2,
+			payload:     v1JSON,
+			failuresErr: errors.New("db is down"),
+			wantStatus:  inbox.EventStatusPending,
+		},
+		{
+			name:       "bad payload",
+			version:    1,
+			payload:    []byte(`{"account_id": 1}`),
+			wantStatus: inbox.EventStatusFailed,
+			wantReason: "cannot unmarshal number",
+		},
+		{
+			name:        "internal error is retried",
+			version:     1,
+			payload:     v1JSON,
+			domainErr:   errors.New("db is down"),
+			wantStatus:  inbox.EventStatusPending,
+			wantCreated: []string{"alice"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &fakeDomain{err: tt.domainErr}
+			f := &fakeFailures{err: tt.failuresErr, reasons: make(map[uuid.UUID]string)}
+			i := New(logium.New(), d, f, DefaultVersions())
+
+			eventID := uuid.New()
+			status := i.AccountCreated(context.Background(), inbox.Event{
+				ID:      eventID,
+				Key:     accountID.String(),
+				Type:    contracts.AccountCreatedEvent,
+				Version: tt.version,
+				Payload: tt.payload,
+			})
+			if status != tt.wantStatus {
+				t.Fatalf("AccountCreated() = %s, want %s", status, tt.wantStatus)
+			}
+			if len(d.created) != len(tt.wantCreated) || (len(d.created) > 0 && d.created[0] != tt.wantCreated[0]) {
+				t.Fatalf("created profiles = %v, want %v", d.created, tt.wantCreated)
+			}
+			// the stored reason is the decoding error, wantReason is a part of it
+			reason, stored := f.reasons[eventID]
+			if stored != (tt.wantReason != "") || !strings.Contains(reason, tt.wantReason) {
+				t.Fatalf("failure reason = %q, want one containing %q", reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestAccountUsernameUpdatedUpcast(t *testing.T) {
+	accountID := uuid.New()
+	at := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	jsonOf := func(v any) []byte {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	tests := []struct {
+		name        string
+		version     int32
+		payload     []byte
+		wantStatus  inbox.EventStatus
+		wantUpdated []string
+	}{
+		{
+			name:    "v1 is upcast to v2",
+			version: 1,
+			payload: jsonOf(contracts.AccountUsernameUpdatedPayload{
+				AccountID: accountID, NewUsername: "bob", UpdatedAt: at,
+			}),
+			wantStatus:  inbox.EventStatusProcessed,
+			wantUpdated: []string{"bob"},
+		},
+		{
+			name:    "v2 is decoded as is",
+			version: 2,
+			payload: jsonOf(contracts.AccountUsernameUpdatedPayloadV2{
+				AccountID: accountID, Username: "carol", OldUsername: "bob", UpdatedAt: at,
+			}),
+			wantStatus:  inbox.EventStatusProcessed,
+			wantUpdated: []string{"carol"},
+		},
+		{
+			name:    "v2 field names are not read from v1",
+			version: 1,
+			payload: jsonOf(contracts.AccountUsernameUpdatedPayloadV2{
+				AccountID: accountID, Username: "carol", UpdatedAt: at,
+			}),
+			wantStatus:  inbox.EventStatusProcessed,
+			wantUpdated: []string{""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &fakeDomain{}
+			i := New(logium.New(), d, &fakeFailures{reasons: make(map[uuid.UUID]string)}, DefaultVersions())
+
+			status := i.AccountUsernameUpdated(context.Background(), inbox.Event{
+				ID:      uuid.New(),
+				Key:     accountID.String(),
+				Type:    contracts.AccountUsernameUpdatedEvent,
+				Version: tt.version,
+				Payload: tt.payload,
+			})
+			if status != tt.wantStatus {
+				t.Fatalf("AccountUsernameUpdated() = %s, want %s", status, tt.wantStatus)
+			}
+			if fmt.Sprint(d.updated) != fmt.Sprint(tt.wantUpdated) {
+				t.Fatalf("updated usernames = %v, want %v", d.updated, tt.wantUpdated)
+			}
+		})
+	}
+}
+
+func TestDecodeEventTypeMismatch(t *testing.T) {
+	v := DefaultVersions()
+
+	_, err := decodeEvent[contracts.AccountDeletedPayload](v, inbox.Event{
+		Type:    contracts.AccountCreatedEvent,
+		Version: 1,
+		Payload: []byte(`{}`),
+	})
+	if err == nil || errors.Is(err, ErrUnknownEventVersion) {
+		t.Fatalf("decodeEvent() error = %v, want type mismatch", err)
+	}
+}
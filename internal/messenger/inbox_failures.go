@@ -0,0 +1,23 @@
+package messenger
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+const upsertInboxEventFailureQuery = `
+INSERT INTO inbox_event_failures (event_id, reason, failed_at)
+VALUES ($1, $2, now() AT TIME ZONE 'UTC')
+ON CONFLICT (event_id) DO UPDATE SET reason = EXCLUDED.reason, failed_at = EXCLUDED.failed_at`
+
+// RecordInboxEventFailure stores why an inbox event was marked failed,
+// a later failure of the same event replaces the reason.
+func (m *Messenger) RecordInboxEventFailure(ctx context.Context, eventID uuid.UUID, reason string) error {
+	if _, err := m.db.Exec(ctx, upsertInboxEventFailureQuery, eventID, reason); err != nil {
+		return fmt.Errorf("failed to record failure of inbox event %s: %w", eventID, err)
+	}
+
+	return nil
+}
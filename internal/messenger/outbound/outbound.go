@@ -1,19 +1,118 @@
 package outbound
 
 import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
 	"github.com/netbill/evebox/box/outbox"
+	"github.com/netbill/evebox/header"
 	"github.com/netbill/logium"
 	"github.com/netbill/pgdbx"
+	"github.com/netbill/profiles-svc/internal/core/models"
+	"github.com/netbill/profiles-svc/internal/messenger/contracts"
+	"github.com/segmentio/kafka-go"
 )
 
 type Outbound struct {
 	log    *logium.Logger
-	outbox outbox.Box
+	outbox outboxWriter
+
+	profilesV1 bool
+	profilesV2 bool
+	codecs     contracts.Codecs
+}
+
+// Config selects the profile topics events are written to. During the
+// migration to profiles.v2 both are written, profiles.v1 is dropped once
+// its consumers moved on.
+type Config struct {
+	ProfilesV1 bool
+	ProfilesV2 bool
+
+	// Codecs picks the payload encoding of every topic.
+	Codecs contracts.Codecs
 }
 
-func New(log *logium.Logger, pool *pgdbx.DB) *Outbound {
+// outboxWriter queues messages for the producer, outbox.Box writes them to the outbox table.
+type outboxWriter interface {
+	CreateOutboxEvent(ctx context.Context, msg kafka.Message) (outbox.Event, error)
+}
+
+func New(log *logium.Logger, pool *pgdbx.DB, cfg Config) *Outbound {
 	return &Outbound{
-		log:    log,
-		outbox: outbox.New(pool),
+		log:        log,
+		outbox:     outbox.New(pool),
+		profilesV1: cfg.ProfilesV1,
+		profilesV2: cfg.ProfilesV2,
+		codecs:     cfg.Codecs,
+	}
+}
+
+// writeEvent encodes payload with the codec of the topic and queues it in the outbox.
+func (o *Outbound) writeEvent(
+	ctx context.Context,
+	topic string,
+	eventType string,
+	version string,
+	key uuid.UUID,
+	payload any,
+) (outbox.Event, error) {
+	codec := o.codecs.ForTopic(topic)
+
+	value, err := codec.Marshal(payload)
+	if err != nil {
+		return outbox.Event{}, fmt.Errorf("failed to marshal %s payload, cause: %w", eventType, err)
+	}
+
+	event, err := o.outbox.CreateOutboxEvent(
+		ctx,
+		kafka.Message{
+			Topic: topic,
+			Key:   []byte(key.String()),
+			Value: value,
+			Headers: []kafka.Header{
+				{Key: header.EventID, Value: []byte(uuid.New().String())},
+				{Key: header.EventType, Value: []byte(eventType)},
+				{Key: header.EventVersion, Value: []byte(version)},
+				{Key: header.Producer, Value: []byte(contracts.ProfilesSvcGroup)},
+				{Key: header.ContentType, Value: []byte(codec.ContentType())},
+			},
+		},
+	)
+	if err != nil {
+		return outbox.Event{}, fmt.Errorf("failed to create outbox event for %s on %s, cause: %w", eventType, topic, err)
+	}
+
+	return event, nil
+}
+
+func profileFieldChanges(changes models.ProfileChanges) []contracts.ProfileFieldChange {
+	res := make([]contracts.ProfileFieldChange, 0, len(changes))
+	for _, ch := range changes {
+		res = append(res, contracts.ProfileFieldChange{
+			Field:         string(ch.Field),
+			Old:           ch.Old,
+			New:           ch.New,
+			ValuesOmitted: ch.Field.Media(),
+		})
+	}
+
+	return res
+}
+
+func profileV2(profile models.Profile) contracts.ProfileV2 {
+	return contracts.ProfileV2{
+		AccountID:      profile.AccountID,
+		Username:       profile.Username,
+		Official:       profile.Official,
+		Pseudonym:      profile.Pseudonym,
+		Description:    profile.Description,
+		Avatar:         profile.Avatar,
+		AvatarVariants: profile.AvatarVariants,
+		Banner:         profile.Banner,
+		Version:        profile.Version,
+		CreatedAt:      profile.CreatedAt,
+		UpdatedAt:      profile.UpdatedAt,
 	}
 }
@@ -2,49 +2,42 @@ package outbound
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 
-	"github.com/google/uuid"
-	"github.com/netbill/evebox/header"
 	"github.com/netbill/profiles-svc/internal/core/models"
 	"github.com/netbill/profiles-svc/internal/messenger/contracts"
-	"github.com/segmentio/kafka-go"
 )
 
 func (o *Outbound) WriteProfileCreated(
 	ctx context.Context,
 	profile models.Profile,
 ) error {
-	payload, err := json.Marshal(contracts.ProfileCreatedPayload{
-		AccountID: profile.AccountID,
-		Username:  profile.Username,
-		CreatedAt: profile.CreatedAt,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to marshal profile created payload: %w", err)
+	if o.profilesV1 {
+		event, err := o.writeEvent(ctx, contracts.ProfilesTopicV1, contracts.ProfileCreatedEvent, "1", profile.AccountID,
+			contracts.ProfileCreatedPayload{
+				AccountID: profile.AccountID,
+				Username:  profile.Username,
+				CreatedAt: profile.CreatedAt,
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		o.log.Debugf("profile created event queued, account_id: %s, event_id: %s", profile.AccountID, event.ID)
 	}
 
-	event, err := o.outbox.CreateOutboxEvent(
-		ctx,
-		kafka.Message{
-			Topic: contracts.ProfilesTopicV1,
-			Key:   []byte(profile.AccountID.String()),
-			Value: payload,
-			Headers: []kafka.Header{
-				{Key: header.EventID, Value: []byte(uuid.New().String())},
-				{Key: header.EventType, Value: []byte(contracts.ProfileCreatedEvent)},
-				{Key: header.EventVersion, Value: []byte("1")},
-				{Key: header.Producer, Value: []byte(contracts.ProfilesSvcGroup)},
-				{Key: header.ContentType, Value: []byte("application/json")},
+	if o.profilesV2 {
+		event, err := o.writeEvent(ctx, contracts.ProfilesTopicV2, contracts.ProfileCreatedEvent, "2", profile.AccountID,
+			contracts.ProfileCreatedPayloadV2{
+				Profile: profileV2(profile),
 			},
-		},
-	)
-	if err != nil {
-		return fmt.Errorf("failed to create outbox event for profile created: %w", err)
-	}
+		)
+		if err != nil {
+			return err
+		}
 
-	o.log.Debugf("profile created event queued, account_id: %s, event_id: %s", profile.AccountID, event.ID)
+		o.log.Debugf("profile created v2 event queued, account_id: %s, event_id: %s", profile.AccountID, event.ID)
+	}
 
 	return nil
 }
@@ -2,48 +2,46 @@ package outbound
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"time"
 
-	"github.com/google/uuid"
-	"github.com/netbill/evebox/header"
+	"github.com/netbill/profiles-svc/internal/core/models"
 	"github.com/netbill/profiles-svc/internal/messenger/contracts"
-	"github.com/segmentio/kafka-go"
 )
 
 func (o *Outbound) WriteProfileDeleted(
 	ctx context.Context,
-	accountID uuid.UUID,
+	tombstone models.ProfileTombstone,
 ) error {
-	payload, err := json.Marshal(contracts.ProfileDeletedPayload{
-		AccountID: accountID,
-		DeletedAt: time.Now().UTC(),
-	})
-	if err != nil {
-		return fmt.Errorf("failed to marshal profile deleted payload, cause: %w", err)
+	if o.profilesV1 {
+		event, err := o.writeEvent(ctx, contracts.ProfilesTopicV1, contracts.ProfileDeletedEvent, "1", tombstone.AccountID,
+			contracts.ProfileDeletedPayload{
+				AccountID: tombstone.AccountID,
+				DeletedAt: tombstone.DeletedAt,
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		o.log.Debugf("profile deleted event queued, account_id: %s, event_id: %s", tombstone.AccountID, event.ID)
 	}
 
-	event, err := o.outbox.CreateOutboxEvent(
-		ctx,
-		kafka.Message{
-			Topic: contracts.ProfilesTopicV1,
-			Key:   []byte(accountID.String()),
-			Value: payload,
-			Headers: []kafka.Header{
-				{Key: header.EventID, Value: []byte(uuid.New().String())},
-				{Key: header.EventType, Value: []byte(contracts.ProfileDeletedEvent)},
-				{Key: header.EventVersion, Value: []byte("1")},
-				{Key: header.Producer, Value: []byte(contracts.ProfilesSvcGroup)},
-				{Key: header.ContentType, Value: []byte("application/json")},
+	if o.profilesV2 {
+		event, err := o.writeEvent(ctx, contracts.ProfilesTopicV2, contracts.ProfileDeletedEvent, "2", tombstone.AccountID,
+			contracts.ProfileDeletedPayloadV2{
+				AccountID: tombstone.AccountID,
+				Version:   tombstone.Version,
+				DeletedAt: tombstone.DeletedAt,
 			},
-		},
-	)
-	if err != nil {
-		return fmt.Errorf("failed to create outbox event for profile deleted, cause: %w", err)
-	}
+		)
+		if err != nil {
+			return err
+		}
 
-	o.log.Debugf("profile deleted event queued, account_id: %s, event_id: %s", accountID.String(), event.ID)
+		o.log.Debugf(
+			"profile deleted v2 event queued, account_id: %s, version: %d, event_id: %s",
+			tombstone.AccountID, tombstone.Version, event.ID,
+		)
+	}
 
 	return nil
 }
@@ -0,0 +1,48 @@
+package outbound
+
+import (
+	"context"
+
+	"github.com/netbill/profiles-svc/internal/core/models"
+	"github.com/netbill/profiles-svc/internal/messenger/contracts"
+)
+
+func (o *Outbound) WriteProfileSnapshot(
+	ctx context.Context,
+	profile models.Profile,
+) error {
+	event, err := o.writeEvent(ctx, contracts.ProfilesSnapshotTopicV1, contracts.ProfileSnapshotEvent, "1", profile.AccountID,
+		contracts.ProfileSnapshotPayload{
+			Profile: profileV2(profile),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	o.log.Debugf("profile snapshot event queued, account_id: %s, event_id: %s", profile.AccountID, event.ID)
+
+	return nil
+}
+
+// WriteProfileSnapshotTombstone replaces the snapshot of a deleted profile with a
+// profile.snapshot.deleted marker, the outbox keeps no messages without a value.
+func (o *Outbound) WriteProfileSnapshotTombstone(
+	ctx context.Context,
+	tombstone models.ProfileTombstone,
+) error {
+	event, err := o.writeEvent(ctx, contracts.ProfilesSnapshotTopicV1, contracts.ProfileSnapshotDeletedEvent, "1", tombstone.AccountID,
+		contracts.ProfileSnapshotDeletedPayload{
+			AccountID: tombstone.AccountID,
+			Version:   tombstone.Version,
+			DeletedAt: tombstone.DeletedAt,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	o.log.Debugf("profile snapshot tombstone queued, account_id: %s, event_id: %s", tombstone.AccountID, event.ID)
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package outbound
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/netbill/evebox/box/outbox"
+	"github.com/netbill/evebox/header"
+	"github.com/netbill/logium"
+	"github.com/netbill/profiles-svc/internal/core/models"
+	"github.com/netbill/profiles-svc/internal/messenger/contracts"
+	"github.com/segmentio/kafka-go"
+)
+
+type fakeOutbox struct {
+	messages []kafka.Message
+}
+
+func (o *fakeOutbox) CreateOutboxEvent(_ context.Context, msg kafka.Message) (outbox.Event, error) {
+	o.messages = append(o.messages, msg)
+	return outbox.Event{ID: uuid.New()}, nil
+}
+
+func messageHeader(msg kafka.Message, key string) string {
+	for _, h := range msg.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+
+	return ""
+}
+
+func TestWriteProfileSnapshotTombstone(t *testing.T) {
+	box := &fakeOutbox{}
+	o := &Outbound{log: logium.New(), outbox: box}
+
+	tombstone := models.ProfileTombstone{
+		AccountID: uuid.New(),
+		Version:   7,
+		DeletedAt: time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+	}
+	if err := o.WriteProfileSnapshotTombstone(context.Background(), tombstone); err != nil {
+		t.Fatalf("WriteProfileSnapshotTombstone() error = %v", err)
+	}
+
+	if len(box.messages) != 1 {
+		t.Fatalf("queued %d messages, want 1", len(box.messages))
+	}
+	msg := box.messages[0]
+
+	if msg.Topic != contracts.ProfilesSnapshotTopicV1 || string(msg.Key) != tombstone.AccountID.String() {
+		t.Fatalf("message on %s with key %s, want %s with key %s",
+			msg.Topic, msg.Key, contracts.ProfilesSnapshotTopicV1, tombstone.AccountID)
+	}
+	if got := messageHeader(msg, header.EventType); got != contracts.ProfileSnapshotDeletedEvent {
+		t.Fatalf("event type = %q, want %q", got, contracts.ProfileSnapshotDeletedEvent)
+	}
+	if got := messageHeader(msg, header.ContentType); got != contracts.ContentTypeJSON {
+		t.Fatalf("content type = %q, want %q", got, contracts.ContentTypeJSON)
+	}
+
+	// the outbox payload column is jsonb and NOT NULL, the marker has to be a JSON value
+	if !json.Valid(msg.Value) {
+		t.Fatalf("value %q is not JSON", msg.Value)
+	}
+	var payload contracts.ProfileSnapshotDeletedPayload
+	if err := json.Unmarshal(msg.Value, &payload); err != nil {
+		t.Fatal(err)
+	}
+	want := contracts.ProfileSnapshotDeletedPayload{
+		AccountID: tombstone.AccountID,
+		Version:   tombstone.Version,
+		DeletedAt: tombstone.DeletedAt,
+	}
+	if payload != want {
+		t.Fatalf("payload = %+v, want %+v", payload, want)
+	}
+}
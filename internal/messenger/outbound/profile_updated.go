@@ -2,52 +2,86 @@ package outbound
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 
-	"github.com/google/uuid"
-	"github.com/netbill/evebox/header"
 	"github.com/netbill/profiles-svc/internal/core/models"
 	"github.com/netbill/profiles-svc/internal/messenger/contracts"
-	"github.com/segmentio/kafka-go"
 )
 
+// profileFieldEvents are the dedicated events of the fields that have one.
+var profileFieldEvents = map[models.ProfileField]string{
+	models.ProfileFieldUsername: contracts.ProfileUsernameChangedEvent,
+	models.ProfileFieldOfficial: contracts.ProfileOfficialChangedEvent,
+	models.ProfileFieldAvatar:   contracts.ProfileAvatarChangedEvent,
+	models.ProfileFieldBanner:   contracts.ProfileBannerChangedEvent,
+}
+
+// WriteProfileUpdated writes profile.updated, on profiles.v2 with the changed
+// fields followed by the dedicated event of every changed field that has one.
+// The payloads of profiles.v1 stay as they were.
 func (o *Outbound) WriteProfileUpdated(
 	ctx context.Context,
 	profile models.Profile,
+	changes models.ProfileChanges,
 ) error {
-	payload, err := json.Marshal(contracts.ProfileUpdatedPayload{
-		AccountID:   profile.AccountID,
-		Username:    profile.Username,
-		Official:    profile.Official,
-		Pseudonym:   profile.Pseudonym,
-		Description: profile.Description,
-		UpdatedAt:   profile.UpdatedAt,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to marshal profile updated payload, cause: %w", err)
-	}
+	if o.profilesV1 {
+		event, err := o.writeEvent(ctx, contracts.ProfilesTopicV1, contracts.ProfileUpdatedEvent, "1", profile.AccountID,
+			contracts.ProfileUpdatedPayload{
+				AccountID:   profile.AccountID,
+				Username:    profile.Username,
+				Official:    profile.Official,
+				Pseudonym:   profile.Pseudonym,
+				Description: profile.Description,
 
-	event, err := o.outbox.CreateOutboxEvent(
-		ctx,
-		kafka.Message{
-			Topic: contracts.ProfilesTopicV1,
-			Key:   []byte(profile.AccountID.String()),
-			Value: payload,
-			Headers: []kafka.Header{
-				{Key: header.EventID, Value: []byte(uuid.New().String())},
-				{Key: header.EventType, Value: []byte(contracts.ProfileUpdatedEvent)},
-				{Key: header.EventVersion, Value: []byte("1")},
-				{Key: header.Producer, Value: []byte(contracts.ProfilesSvcGroup)},
-				{Key: header.ContentType, Value: []byte("application/json")},
+				UpdatedAt: profile.UpdatedAt,
 			},
+		)
+		if err != nil {
+			return err
+		}
+
+		o.log.Debugf("profile updated event queued, account_id: %s, event_id: %s", profile.AccountID, event.ID)
+	}
+
+	if !o.profilesV2 {
+		return nil
+	}
+
+	event, err := o.writeEvent(ctx, contracts.ProfilesTopicV2, contracts.ProfileUpdatedEvent, "2", profile.AccountID,
+		contracts.ProfileUpdatedPayloadV2{
+			Profile:       profileV2(profile),
+			ChangedFields: profileFieldChanges(changes),
 		},
 	)
 	if err != nil {
-		return fmt.Errorf("failed to create outbox event for profile updated, cause: %w", err)
+		return err
 	}
 
-	o.log.Debugf("profile updated event queued, account_id: %s, event_id: %s", profile.AccountID, event.ID)
+	o.log.Debugf(
+		"profile updated v2 event queued, account_id: %s, version: %d, event_id: %s",
+		profile.AccountID, profile.Version, event.ID,
+	)
+
+	for _, change := range changes {
+		eventType, ok := profileFieldEvents[change.Field]
+		if !ok {
+			continue
+		}
+
+		event, err := o.writeEvent(ctx, contracts.ProfilesTopicV2, eventType, "2", profile.AccountID,
+			contracts.ProfileFieldChangedPayload{
+				AccountID: profile.AccountID,
+				Old:       change.Old,
+				New:       change.New,
+				Version:   profile.Version,
+				ChangedAt: profile.UpdatedAt,
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		o.log.Debugf("%s event queued, account_id: %s, event_id: %s", eventType, profile.AccountID, event.ID)
+	}
 
 	return nil
 }
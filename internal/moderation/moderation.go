@@ -0,0 +1,48 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+type Verdict string
+
+const (
+	// VerdictAllow lets the image go live.
+	VerdictAllow Verdict = "allow"
+	// VerdictReject refuses the image, it is not stored.
+	VerdictReject Verdict = "reject"
+	// VerdictQuarantine holds the image for manual review, it does not go live.
+	VerdictQuarantine Verdict = "quarantine"
+)
+
+func (v Verdict) Validate() error {
+	switch v {
+	case VerdictAllow, VerdictReject, VerdictQuarantine:
+		return nil
+	default:
+		return fmt.Errorf("unknown moderation verdict %q", v)
+	}
+}
+
+// Input is an uploaded image after it was decoded and re-encoded.
+type Input struct {
+	AccountID   uuid.UUID
+	Kind        string
+	ContentType string
+	Data        []byte
+}
+
+type Decision struct {
+	Verdict Verdict `json:"verdict"`
+	Reason  string  `json:"reason,omitempty"`
+}
+
+// Noop allows every image.
+type Noop struct{}
+
+func (Noop) ModerateAvatar(context.Context, Input) (Decision, error) {
+	return Decision{Verdict: VerdictAllow}, nil
+}
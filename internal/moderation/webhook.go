@@ -0,0 +1,80 @@
+package moderation
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// maxWebhookResponse caps the decision body read from the webhook.
+const maxWebhookResponse = 64 << 10
+
+// Webhook posts the image bytes to an external moderation service and expects
+// a JSON decision, e.g. {"verdict": "reject", "reason": "nsfw"}.
+// The body is signed with HMAC-SHA256 in the X-Signature header when a secret is set.
+type Webhook struct {
+	url    string
+	secret []byte
+	client *http.Client
+}
+
+type WebhookConfig struct {
+	URL       string
+	SecretKey string
+	Timeout   time.Duration
+}
+
+func NewWebhook(cfg WebhookConfig) (*Webhook, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("moderation webhook url is required")
+	}
+
+	return &Webhook{
+		url:    cfg.URL,
+		secret: []byte(cfg.SecretKey),
+		client: &http.Client{Timeout: cfg.Timeout},
+	}, nil
+}
+
+func (w *Webhook) ModerateAvatar(ctx context.Context, input Input) (Decision, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(input.Data))
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed to build moderation request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", input.ContentType)
+	req.Header.Set("X-Account-ID", input.AccountID.String())
+	req.Header.Set("X-Media-Kind", input.Kind)
+	if len(w.secret) > 0 {
+		mac := hmac.New(sha256.New, w.secret)
+		mac.Write(input.Data)
+		req.Header.Set("X-Signature", hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed to call moderation webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Decision{}, fmt.Errorf("moderation webhook responded with status %d", resp.StatusCode)
+	}
+
+	var decision Decision
+	if err = json.NewDecoder(io.LimitReader(resp.Body, maxWebhookResponse)).Decode(&decision); err != nil {
+		return Decision{}, fmt.Errorf("failed to decode moderation decision: %w", err)
+	}
+	if err = decision.Verdict.Validate(); err != nil {
+		return Decision{}, err
+	}
+
+	return decision, nil
+}
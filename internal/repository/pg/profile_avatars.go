@@ -0,0 +1,170 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/netbill/pgdbx"
+	"github.com/netbill/profiles-svc/internal/repository"
+)
+
+const profileAvatarsTable = "profile_avatars"
+const ProfileAvatarsColumns = "id, account_id, key, variants, hash, width, height, created_at"
+
+func scanProfileAvatar(row sq.RowScanner) (a repository.ProfileAvatarRow, err error) {
+	err = row.Scan(
+		&a.ID,
+		&a.AccountID,
+		&a.Key,
+		&a.Variants,
+		&a.Hash,
+		&a.Width,
+		&a.Height,
+		&a.CreatedAt,
+	)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return repository.ProfileAvatarRow{}, nil
+	case err != nil:
+		return repository.ProfileAvatarRow{}, fmt.Errorf("scanning profile avatar: %w", err)
+	}
+
+	return a, nil
+}
+
+type profileAvatars struct {
+	db       *pgdbx.DB
+	selector sq.SelectBuilder
+	inserter sq.InsertBuilder
+	deleter  sq.DeleteBuilder
+}
+
+func NewProfileAvatarsQ(db *pgdbx.DB) repository.ProfileAvatarsQ {
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	return &profileAvatars{
+		db:       db,
+		selector: builder.Select(ProfileAvatarsColumns).From(profileAvatarsTable),
+		inserter: builder.Insert(profileAvatarsTable),
+		deleter:  builder.Delete(profileAvatarsTable),
+	}
+}
+
+func (q *profileAvatars) New() repository.ProfileAvatarsQ {
+	return NewProfileAvatarsQ(q.db)
+}
+
+func (q *profileAvatars) Upsert(
+	ctx context.Context,
+	input repository.ProfileAvatarRow,
+) (repository.ProfileAvatarRow, error) {
+	var variants any
+	if len(input.Variants) > 0 {
+		variants = input.Variants
+	}
+
+	query, args, err := q.inserter.SetMap(map[string]interface{}{
+		"id":         input.ID,
+		"account_id": input.AccountID,
+		"key":        input.Key,
+		"variants":   variants,
+		"hash":       input.Hash,
+		"width":      input.Width,
+		"height":     input.Height,
+	}).Suffix(
+		"ON CONFLICT (account_id, hash) DO UPDATE SET " +
+			"key = EXCLUDED.key, variants = EXCLUDED.variants, " +
+			"width = EXCLUDED.width, height = EXCLUDED.height, created_at = now() " +
+			"RETURNING " + ProfileAvatarsColumns,
+	).ToSql()
+	if err != nil {
+		return repository.ProfileAvatarRow{}, fmt.Errorf("building upsert query for %s: %w", profileAvatarsTable, err)
+	}
+
+	return scanProfileAvatar(q.db.QueryRow(ctx, query, args...))
+}
+
+func (q *profileAvatars) Get(ctx context.Context) (repository.ProfileAvatarRow, error) {
+	query, args, err := q.selector.Limit(1).ToSql()
+	if err != nil {
+		return repository.ProfileAvatarRow{}, fmt.Errorf("building get query for %s: %w", profileAvatarsTable, err)
+	}
+
+	return scanProfileAvatar(q.db.QueryRow(ctx, query, args...))
+}
+
+func (q *profileAvatars) Select(ctx context.Context) ([]repository.ProfileAvatarRow, error) {
+	query, args, err := q.selector.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building select query for %s: %w", profileAvatarsTable, err)
+	}
+
+	rows, err := q.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]repository.ProfileAvatarRow, 0)
+	for rows.Next() {
+		a, err := scanProfileAvatar(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning profile avatar: %w", err)
+		}
+		out = append(out, a)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (q *profileAvatars) Delete(ctx context.Context) error {
+	query, args, err := q.deleter.ToSql()
+	if err != nil {
+		return fmt.Errorf("building delete query for %s: %w", profileAvatarsTable, err)
+	}
+
+	_, err = q.db.Exec(ctx, query, args...)
+	return err
+}
+
+func (q *profileAvatars) FilterID(id ...uuid.UUID) repository.ProfileAvatarsQ {
+	q.selector = q.selector.Where(sq.Eq{"id": id})
+	q.deleter = q.deleter.Where(sq.Eq{"id": id})
+	return q
+}
+
+func (q *profileAvatars) FilterAccountID(accountID uuid.UUID) repository.ProfileAvatarsQ {
+	q.selector = q.selector.Where(sq.Eq{"account_id": accountID})
+	q.deleter = q.deleter.Where(sq.Eq{"account_id": accountID})
+	return q
+}
+
+func (q *profileAvatars) FilterKey(key string) repository.ProfileAvatarsQ {
+	q.selector = q.selector.Where(sq.Eq{"key": key})
+	q.deleter = q.deleter.Where(sq.Eq{"key": key})
+	return q
+}
+
+func (q *profileAvatars) OrderByCreatedAt(ascend bool) repository.ProfileAvatarsQ {
+	if ascend {
+		q.selector = q.selector.OrderBy("created_at ASC", "id ASC")
+	} else {
+		q.selector = q.selector.OrderBy("created_at DESC", "id DESC")
+	}
+	return q
+}
+
+func (q *profileAvatars) Page(limit, offset uint) repository.ProfileAvatarsQ {
+	if limit > 0 {
+		q.selector = q.selector.Limit(uint64(limit))
+	}
+	q.selector = q.selector.Offset(uint64(offset))
+	return q
+}
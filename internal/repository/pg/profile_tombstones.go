@@ -0,0 +1,138 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/netbill/pgdbx"
+	"github.com/netbill/profiles-svc/internal/repository"
+)
+
+const profileTombstonesTable = "profile_tombstones"
+const ProfileTombstonesColumns = "account_id, version, deleted_at"
+
+func scanProfileTombstone(row sq.RowScanner) (t repository.ProfileTombstoneRow, err error) {
+	err = row.Scan(
+		&t.AccountID,
+		&t.Version,
+		&t.DeletedAt,
+	)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return repository.ProfileTombstoneRow{}, nil
+	case err != nil:
+		return repository.ProfileTombstoneRow{}, fmt.Errorf("scanning profile tombstone: %w", err)
+	}
+
+	return t, nil
+}
+
+type profileTombstones struct {
+	db       *pgdbx.DB
+	selector sq.SelectBuilder
+	inserter sq.InsertBuilder
+	deleter  sq.DeleteBuilder
+}
+
+func NewProfileTombstonesQ(db *pgdbx.DB) repository.ProfileTombstonesQ {
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	return &profileTombstones{
+		db:       db,
+		selector: builder.Select(ProfileTombstonesColumns).From(profileTombstonesTable),
+		inserter: builder.Insert(profileTombstonesTable),
+		deleter:  builder.Delete(profileTombstonesTable),
+	}
+}
+
+func (q *profileTombstones) New() repository.ProfileTombstonesQ {
+	return NewProfileTombstonesQ(q.db)
+}
+
+func (q *profileTombstones) Upsert(
+	ctx context.Context,
+	accountID uuid.UUID,
+	version int64,
+) (repository.ProfileTombstoneRow, error) {
+	query, args, err := q.inserter.SetMap(map[string]interface{}{
+		"account_id": accountID,
+		"version":    version,
+	}).Suffix(
+		"ON CONFLICT (account_id) DO UPDATE SET deleted_at = now(), " +
+			"version = GREATEST(" + profileTombstonesTable + ".version, EXCLUDED.version) " +
+			"RETURNING " + ProfileTombstonesColumns,
+	).ToSql()
+	if err != nil {
+		return repository.ProfileTombstoneRow{}, fmt.Errorf(
+			"building upsert query for %s: %w", profileTombstonesTable, err,
+		)
+	}
+
+	return scanProfileTombstone(q.db.QueryRow(ctx, query, args...))
+}
+
+func (q *profileTombstones) Select(ctx context.Context) ([]repository.ProfileTombstoneRow, error) {
+	query, args, err := q.selector.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building select query for %s: %w", profileTombstonesTable, err)
+	}
+
+	rows, err := q.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]repository.ProfileTombstoneRow, 0)
+	for rows.Next() {
+		t, err := scanProfileTombstone(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning profile tombstone: %w", err)
+		}
+		out = append(out, t)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (q *profileTombstones) Delete(ctx context.Context) error {
+	query, args, err := q.deleter.ToSql()
+	if err != nil {
+		return fmt.Errorf("building delete query for %s: %w", profileTombstonesTable, err)
+	}
+
+	_, err = q.db.Exec(ctx, query, args...)
+	return err
+}
+
+func (q *profileTombstones) DeleteOne(ctx context.Context) (repository.ProfileTombstoneRow, error) {
+	query, args, err := q.deleter.Suffix("RETURNING " + ProfileTombstonesColumns).ToSql()
+	if err != nil {
+		return repository.ProfileTombstoneRow{}, fmt.Errorf(
+			"building delete query for %s: %w", profileTombstonesTable, err,
+		)
+	}
+
+	return scanProfileTombstone(q.db.QueryRow(ctx, query, args...))
+}
+
+func (q *profileTombstones) FilterAccountID(accountID uuid.UUID) repository.ProfileTombstonesQ {
+	q.selector = q.selector.Where(sq.Eq{"account_id": accountID})
+	q.deleter = q.deleter.Where(sq.Eq{"account_id": accountID})
+	return q
+}
+
+func (q *profileTombstones) Keyset(limit uint, after *uuid.UUID) repository.ProfileTombstonesQ {
+	if after != nil {
+		q.selector = q.selector.Where(sq.Gt{"account_id": *after})
+	}
+	q.selector = q.selector.OrderBy("account_id ASC").Limit(uint64(limit))
+	return q
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
@@ -15,12 +16,21 @@ import (
 )
 
 const profilesTable = "profiles"
-const ProfilesColumns = "account_id, username, official, pseudonym, description, avatar, created_at, updated_at"
+const ProfilesColumns = "account_id, username, official, pseudonym, description, avatar, avatar_variants, " +
+	"banner, version, created_at, updated_at"
+
+// profilesSearchVector must match the idx_profiles_search_vector index expression.
+const profilesSearchVector = "to_tsvector('simple', coalesce(username, '') || ' ' || " +
+	"coalesce(pseudonym, '') || ' ' || coalesce(description, ''))"
+
+// profilesOfficialBoost is added to the search rank of official profiles.
+const profilesOfficialBoost = 0.5
 
 func scanProfile(row sq.RowScanner) (p repository.ProfileRow, err error) {
 	pseudonym := pgtype.Text{}
 	description := pgtype.Text{}
 	avatarURL := pgtype.Text{}
+	banner := pgtype.Text{}
 
 	err = row.Scan(
 		&p.AccountID,
@@ -29,6 +39,9 @@ func scanProfile(row sq.RowScanner) (p repository.ProfileRow, err error) {
 		&pseudonym,
 		&description,
 		&avatarURL,
+		&p.AvatarVariants,
+		&banner,
+		&p.Version,
 		&p.CreatedAt,
 		&p.UpdatedAt,
 	)
@@ -48,10 +61,34 @@ func scanProfile(row sq.RowScanner) (p repository.ProfileRow, err error) {
 	if avatarURL.Valid {
 		p.Avatar = &avatarURL.String
 	}
+	if banner.Valid {
+		p.Banner = &banner.String
+	}
 
 	return p, nil
 }
 
+// profilesSortColumns maps sortable columns to their value in a row,
+// the values are used to build keyset conditions.
+var profilesSortColumns = map[string]func(p repository.ProfileRow) any{
+	"account_id": func(p repository.ProfileRow) any { return p.AccountID },
+	"username":   func(p repository.ProfileRow) any { return p.Username },
+	"official":   func(p repository.ProfileRow) any { return p.Official },
+	"created_at": func(p repository.ProfileRow) any { return p.CreatedAt },
+	"updated_at": func(p repository.ProfileRow) any { return p.UpdatedAt },
+}
+
+type profilesOrder struct {
+	field  string
+	ascend bool
+}
+
+type profilesKeyset struct {
+	limit    uint
+	from     *repository.ProfileRow
+	backward bool
+}
+
 type profiles struct {
 	db       *pgdbx.DB
 	selector sq.SelectBuilder
@@ -59,13 +96,18 @@ type profiles struct {
 	updater  sq.UpdateBuilder
 	deleter  sq.DeleteBuilder
 	counter  sq.SelectBuilder
+
+	orders []profilesOrder
+	rank   sq.Sqlizer
+	keyset *profilesKeyset
+	err    error
 }
 
 func NewProfilesQ(db *pgdbx.DB) repository.ProfilesQ {
 	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	return &profiles{
 		db:       db,
-		selector: builder.Select("profiles.*").From(profilesTable),
+		selector: builder.Select(ProfilesColumns).From(profilesTable),
 		inserter: builder.Insert(profilesTable),
 		updater:  builder.Update(profilesTable),
 		deleter:  builder.Delete(profilesTable),
@@ -84,6 +126,7 @@ func (q *profiles) Insert(ctx context.Context, input repository.ProfileRow) (rep
 		"official":    input.Official,
 		"pseudonym":   input.Pseudonym,
 		"description": input.Description,
+		"version":     input.Version,
 	}).Suffix("RETURNING " + ProfilesColumns).ToSql()
 	if err != nil {
 		return repository.ProfileRow{}, fmt.Errorf("building insert query for %s: %w", profilesTable, err)
@@ -93,7 +136,9 @@ func (q *profiles) Insert(ctx context.Context, input repository.ProfileRow) (rep
 }
 
 func (q *profiles) UpdateMany(ctx context.Context) (int64, error) {
-	q.updater = q.updater.Set("updated_at", time.Now().UTC())
+	q.updater = q.updater.
+		Set("updated_at", time.Now().UTC()).
+		Set("version", sq.Expr("version + 1"))
 
 	query, args, err := q.updater.ToSql()
 	if err != nil {
@@ -108,7 +153,9 @@ func (q *profiles) UpdateMany(ctx context.Context) (int64, error) {
 }
 
 func (q *profiles) UpdateOne(ctx context.Context) (repository.ProfileRow, error) {
-	q.updater = q.updater.Set("updated_at", time.Now().UTC())
+	q.updater = q.updater.
+		Set("updated_at", time.Now().UTC()).
+		Set("version", sq.Expr("version + 1"))
 
 	query, args, err := q.updater.Suffix("RETURNING " + ProfilesColumns).ToSql()
 	if err != nil {
@@ -143,6 +190,21 @@ func (q *profiles) UpdateAvatar(v *string) repository.ProfilesQ {
 	return q
 }
 
+func (q *profiles) UpdateAvatarVariants(v map[string]string) repository.ProfilesQ {
+	if len(v) == 0 {
+		q.updater = q.updater.Set("avatar_variants", nil)
+		return q
+	}
+
+	q.updater = q.updater.Set("avatar_variants", v)
+	return q
+}
+
+func (q *profiles) UpdateBanner(v *string) repository.ProfilesQ {
+	q.updater = q.updater.Set("banner", v)
+	return q
+}
+
 func (q *profiles) Get(ctx context.Context) (repository.ProfileRow, error) {
 	query, args, err := q.selector.Limit(1).ToSql()
 	if err != nil {
@@ -153,7 +215,11 @@ func (q *profiles) Get(ctx context.Context) (repository.ProfileRow, error) {
 }
 
 func (q *profiles) Select(ctx context.Context) ([]repository.ProfileRow, error) {
-	query, args, err := q.selector.ToSql()
+	if q.err != nil {
+		return nil, fmt.Errorf("building select query for %s: %w", profilesTable, q.err)
+	}
+
+	query, args, err := q.ordered().ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("building select query for %s: %w", profilesTable, err)
 	}
@@ -204,24 +270,59 @@ func (q *profiles) FilterOfficial(official bool) repository.ProfilesQ {
 	return q
 }
 
-func (q *profiles) FilterLikePseudonym(pseudonym string) repository.ProfilesQ {
-	q.selector = q.selector.Where(sq.ILike{"pseudonym": "%" + pseudonym + "%"})
-	q.counter = q.counter.Where(sq.ILike{"pseudonym": "%" + pseudonym + "%"})
-	q.updater = q.updater.Where(sq.ILike{"pseudonym": "%" + pseudonym + "%"})
-	q.deleter = q.deleter.Where(sq.ILike{"pseudonym": "%" + pseudonym + "%"})
+func (q *profiles) FilterVersion(version int64) repository.ProfilesQ {
+	q.selector = q.selector.Where(sq.Eq{"version": version})
+	q.counter = q.counter.Where(sq.Eq{"version": version})
+	q.deleter = q.deleter.Where(sq.Eq{"version": version})
+	q.updater = q.updater.Where(sq.Eq{"version": version})
+	return q
+}
+
+func (q *profiles) FilterPseudonymPrefix(prefix string) repository.ProfilesQ {
+	pattern := escapeLike(prefix) + "%"
+
+	q.selector = q.selector.Where(sq.ILike{"pseudonym": pattern})
+	q.counter = q.counter.Where(sq.ILike{"pseudonym": pattern})
+	q.updater = q.updater.Where(sq.ILike{"pseudonym": pattern})
+	q.deleter = q.deleter.Where(sq.ILike{"pseudonym": pattern})
 
 	return q
 }
 
-func (q *profiles) FilterLikeUsername(username string) repository.ProfilesQ {
-	q.selector = q.selector.Where(sq.ILike{"username": "%" + username + "%"})
-	q.counter = q.counter.Where(sq.ILike{"username": "%" + username + "%"})
-	q.updater = q.updater.Where(sq.ILike{"username": "%" + username + "%"})
-	q.deleter = q.deleter.Where(sq.ILike{"username": "%" + username + "%"})
+func (q *profiles) FilterUsernamePrefix(prefix string) repository.ProfilesQ {
+	pattern := escapeLike(prefix) + "%"
+
+	q.selector = q.selector.Where(sq.ILike{"username": pattern})
+	q.counter = q.counter.Where(sq.ILike{"username": pattern})
+	q.updater = q.updater.Where(sq.ILike{"username": pattern})
+	q.deleter = q.deleter.Where(sq.ILike{"username": pattern})
 
 	return q
 }
 
+func (q *profiles) FilterSearch(text string) repository.ProfilesQ {
+	cond := sq.Or{
+		sq.Expr("username % ?", text),
+		sq.Expr("pseudonym % ?", text),
+		sq.Expr(profilesSearchVector+" @@ plainto_tsquery('simple', ?)", text),
+		sq.ILike{"username": escapeLike(text) + "%"},
+	}
+
+	q.selector = q.selector.Where(cond)
+	q.counter = q.counter.Where(cond)
+	q.updater = q.updater.Where(cond)
+	q.deleter = q.deleter.Where(cond)
+
+	return q
+}
+
+// likeEscaper escapes LIKE wildcards, backslash is the default escape character in postgres.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
 func (q *profiles) Count(ctx context.Context) (uint, error) {
 	query, args, err := q.counter.ToSql()
 	if err != nil {
@@ -243,6 +344,115 @@ func (q *profiles) Page(limit, offset uint) repository.ProfilesQ {
 	return q
 }
 
+func (q *profiles) Keyset(limit uint, from *repository.ProfileRow, backward bool) repository.ProfilesQ {
+	q.keyset = &profilesKeyset{
+		limit:    limit,
+		from:     from,
+		backward: backward,
+	}
+	return q
+}
+
+func (q *profiles) OrderBy(field string, ascend bool) repository.ProfilesQ {
+	if _, ok := profilesSortColumns[field]; !ok {
+		q.err = fmt.Errorf("unknown sort field %q", field)
+		return q
+	}
+
+	q.orders = append(q.orders, profilesOrder{field: field, ascend: ascend})
+	return q
+}
+
+// OrderBySearchRank orders by relevance to text before any OrderBy field.
+// It can not be combined with Keyset, the rank is not part of the cursor.
+func (q *profiles) OrderBySearchRank(text string, boostOfficial bool) repository.ProfilesQ {
+	rank := "GREATEST(similarity(username, ?), similarity(coalesce(pseudonym, ''), ?)) + " +
+		"ts_rank(" + profilesSearchVector + ", plainto_tsquery('simple', ?))"
+	if boostOfficial {
+		rank += fmt.Sprintf(" + CASE WHEN official THEN %v ELSE 0 END", profilesOfficialBoost)
+	}
+
+	q.rank = sq.Expr(rank+" DESC", text, text, text)
+	return q
+}
+
+// ordered applies ORDER BY and the keyset bounds to the selector.
+func (q *profiles) ordered() sq.SelectBuilder {
+	if len(q.orders) == 0 && q.keyset == nil && q.rank == nil {
+		return q.selector
+	}
+
+	orders := make([]profilesOrder, 0, len(q.orders)+1)
+	tiebreak := true
+	for _, o := range q.orders {
+		if q.keyset != nil && q.keyset.backward {
+			o.ascend = !o.ascend
+		}
+		orders = append(orders, o)
+		if o.field == "account_id" {
+			tiebreak = false
+		}
+	}
+	if tiebreak {
+		orders = append(orders, profilesOrder{
+			field:  "account_id",
+			ascend: q.keyset == nil || !q.keyset.backward,
+		})
+	}
+
+	selector := q.selector
+	if q.keyset != nil {
+		if q.keyset.from != nil {
+			selector = selector.Where(keysetCondition(orders, *q.keyset.from))
+		}
+		selector = selector.Limit(uint64(q.keyset.limit))
+	} else if q.rank != nil {
+		selector = selector.OrderByClause(q.rank)
+	}
+
+	for _, o := range orders {
+		if o.ascend {
+			selector = selector.OrderBy(o.field + " ASC")
+		} else {
+			selector = selector.OrderBy(o.field + " DESC")
+		}
+	}
+
+	return selector
+}
+
+// keysetCondition matches rows that come after the boundary row in the given order:
+// (c1 > v1) OR (c1 = v1 AND c2 > v2) OR ...
+func keysetCondition(orders []profilesOrder, from repository.ProfileRow) sq.Or {
+	cond := make(sq.Or, 0, len(orders))
+	for i, o := range orders {
+		and := make(sq.And, 0, i+1)
+		for _, prev := range orders[:i] {
+			and = append(and, sq.Eq{prev.field: profilesSortColumns[prev.field](from)})
+		}
+
+		value := profilesSortColumns[o.field](from)
+		if o.ascend {
+			and = append(and, sq.Gt{o.field: value})
+		} else {
+			and = append(and, sq.Lt{o.field: value})
+		}
+
+		cond = append(cond, and)
+	}
+
+	return cond
+}
+
+func (q *profiles) DeleteOne(ctx context.Context) (repository.ProfileRow, error) {
+	query, args, err := q.deleter.Suffix("RETURNING " + ProfilesColumns).ToSql()
+	if err != nil {
+		return repository.ProfileRow{}, fmt.Errorf("building delete query for %s: %w", profilesTable, err)
+	}
+
+	return scanProfile(q.db.QueryRow(ctx, query, args...))
+}
+
 func (q *profiles) Delete(ctx context.Context) error {
 	query, args, err := q.deleter.ToSql()
 	if err != nil {
@@ -0,0 +1,133 @@
+package pg
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/netbill/profiles-svc/internal/repository"
+)
+
+func TestProfilesKeysetQuery(t *testing.T) {
+	id := uuid.MustParse("5f0c6f5e-2d4b-4d8e-9f57-0b8f4f2b1c11")
+	createdAt := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	from := &repository.ProfileRow{AccountID: id, Username: "alice", Official: true, CreatedAt: createdAt}
+
+	const selectFrom = "SELECT " + ProfilesColumns + " FROM profiles"
+
+	// keyset bounds pass through driver.Valuer, an account id is bound as its string
+	tests := []struct {
+		name      string
+		build     func(q repository.ProfilesQ) repository.ProfilesQ
+		wantQuery string
+		wantArgs  []any
+	}{
+		{
+			name: "first page",
+			build: func(q repository.ProfilesQ) repository.ProfilesQ {
+				return q.OrderBy("created_at", true).Keyset(11, nil, false)
+			},
+			wantQuery: selectFrom + " ORDER BY created_at ASC, account_id ASC LIMIT 11",
+		},
+		{
+			name: "next page",
+			build: func(q repository.ProfilesQ) repository.ProfilesQ {
+				return q.OrderBy("created_at", true).Keyset(11, from, false)
+			},
+			wantQuery: selectFrom + " WHERE ((created_at > $1) OR (created_at = $2 AND account_id > $3))" +
+				" ORDER BY created_at ASC, account_id ASC LIMIT 11",
+			wantArgs: []any{createdAt, createdAt, id.String()},
+		},
+		{
+			name: "previous page reverses the order",
+			build: func(q repository.ProfilesQ) repository.ProfilesQ {
+				return q.OrderBy("created_at", true).Keyset(11, from, true)
+			},
+			wantQuery: selectFrom + " WHERE ((created_at < $1) OR (created_at = $2 AND account_id < $3))" +
+				" ORDER BY created_at DESC, account_id DESC LIMIT 11",
+			wantArgs: []any{createdAt, createdAt, id.String()},
+		},
+		{
+			name: "descending sort with a filter",
+			build: func(q repository.ProfilesQ) repository.ProfilesQ {
+				return q.FilterOfficial(true).OrderBy("official", false).OrderBy("username", true).Keyset(5, from, false)
+			},
+			wantQuery: selectFrom + " WHERE official = $1 AND" +
+				" ((official < $2) OR (official = $3 AND username > $4) OR (official = $5 AND username = $6 AND account_id > $7))" +
+				" ORDER BY official DESC, username ASC, account_id ASC LIMIT 5",
+			wantArgs: []any{true, true, true, "alice", true, "alice", id.String()},
+		},
+		{
+			name: "account id sort needs no tiebreak",
+			build: func(q repository.ProfilesQ) repository.ProfilesQ {
+				return q.OrderBy("account_id", false).Keyset(3, from, false)
+			},
+			wantQuery: selectFrom + " WHERE ((account_id < $1)) ORDER BY account_id DESC LIMIT 3",
+			wantArgs:  []any{id.String()},
+		},
+		{
+			name: "exact filters",
+			build: func(q repository.ProfilesQ) repository.ProfilesQ {
+				return q.FilterAccountID(id).FilterUsername("alice").FilterOfficial(false).Keyset(10, nil, false)
+			},
+			wantQuery: selectFrom + " WHERE account_id IN ($1) AND username = $2 AND official = $3" +
+				" ORDER BY account_id ASC LIMIT 10",
+			wantArgs: []any{id, "alice", false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := tt.build(NewProfilesQ(nil)).(*profiles)
+			if q.err != nil {
+				t.Fatalf("build error = %v", q.err)
+			}
+
+			query, args, err := q.ordered().ToSql()
+			if err != nil {
+				t.Fatalf("ToSql() error = %v", err)
+			}
+			if query != tt.wantQuery {
+				t.Fatalf("query =\n%s\nwant\n%s", query, tt.wantQuery)
+			}
+			if len(args) != 0 || len(tt.wantArgs) != 0 {
+				if !reflect.DeepEqual(args, tt.wantArgs) {
+					t.Fatalf("args = %v, want %v", args, tt.wantArgs)
+				}
+			}
+		})
+	}
+}
+
+func TestProfilesSearchQuery(t *testing.T) {
+	q := NewProfilesQ(nil).FilterSearch("ali_").OrderBySearchRank("ali_", true).OrderBy("username", true).(*profiles)
+
+	query, args, err := q.ordered().ToSql()
+	if err != nil {
+		t.Fatalf("ToSql() error = %v", err)
+	}
+
+	wantQuery := "SELECT " + ProfilesColumns + " FROM profiles WHERE (username % $1 OR pseudonym % $2 OR " +
+		profilesSearchVector + " @@ plainto_tsquery('simple', $3) OR username ILIKE $4)" +
+		" ORDER BY GREATEST(similarity(username, $5), similarity(coalesce(pseudonym, ''), $6)) + " +
+		"ts_rank(" + profilesSearchVector + ", plainto_tsquery('simple', $7)) + " +
+		"CASE WHEN official THEN 0.5 ELSE 0 END DESC, username ASC, account_id ASC"
+	if query != wantQuery {
+		t.Fatalf("query =\n%s\nwant\n%s", query, wantQuery)
+	}
+
+	// the prefix match escapes LIKE wildcards of the search text
+	wantArgs := []any{"ali_", "ali_", "ali_", `ali\_%`, "ali_", "ali_", "ali_"}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Fatalf("args = %#v, want %#v", args, wantArgs)
+	}
+}
+
+func TestProfilesOrderByUnknownField(t *testing.T) {
+	q := NewProfilesQ(nil).OrderBy("pseudonym; DROP TABLE profiles", true).(*profiles)
+	if q.err == nil || !strings.Contains(q.err.Error(), "unknown sort field") {
+		t.Fatalf("OrderBy() error = %v, want unknown sort field", q.err)
+	}
+}
@@ -0,0 +1,189 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/netbill/pgdbx"
+	"github.com/netbill/profiles-svc/internal/repository"
+)
+
+const uploadSessionsTable = "upload_sessions"
+const UploadSessionsColumns = "id, account_id, status, created_at, updated_at, expires_at"
+
+func scanUploadSession(row sq.RowScanner) (s repository.UploadSessionRow, err error) {
+	err = row.Scan(
+		&s.ID,
+		&s.AccountID,
+		&s.Status,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+		&s.ExpiresAt,
+	)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return repository.UploadSessionRow{}, nil
+	case err != nil:
+		return repository.UploadSessionRow{}, fmt.Errorf("scanning upload session: %w", err)
+	}
+
+	return s, nil
+}
+
+type uploadSessions struct {
+	db       *pgdbx.DB
+	selector sq.SelectBuilder
+	inserter sq.InsertBuilder
+	updater  sq.UpdateBuilder
+	deleter  sq.DeleteBuilder
+}
+
+func NewUploadSessionsQ(db *pgdbx.DB) repository.UploadSessionsQ {
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	return &uploadSessions{
+		db:       db,
+		selector: builder.Select(UploadSessionsColumns).From(uploadSessionsTable),
+		inserter: builder.Insert(uploadSessionsTable),
+		updater:  builder.Update(uploadSessionsTable),
+		deleter:  builder.Delete(uploadSessionsTable),
+	}
+}
+
+func (q *uploadSessions) New() repository.UploadSessionsQ {
+	return NewUploadSessionsQ(q.db)
+}
+
+func (q *uploadSessions) Insert(
+	ctx context.Context,
+	input repository.UploadSessionRow,
+) (repository.UploadSessionRow, error) {
+	query, args, err := q.inserter.SetMap(map[string]interface{}{
+		"id":         input.ID,
+		"account_id": input.AccountID,
+		"status":     input.Status,
+		"expires_at": input.ExpiresAt,
+	}).Suffix("RETURNING " + UploadSessionsColumns).ToSql()
+	if err != nil {
+		return repository.UploadSessionRow{}, fmt.Errorf("building insert query for %s: %w", uploadSessionsTable, err)
+	}
+
+	return scanUploadSession(q.db.QueryRow(ctx, query, args...))
+}
+
+func (q *uploadSessions) Get(ctx context.Context) (repository.UploadSessionRow, error) {
+	query, args, err := q.selector.Limit(1).ToSql()
+	if err != nil {
+		return repository.UploadSessionRow{}, fmt.Errorf("building get query for %s: %w", uploadSessionsTable, err)
+	}
+
+	return scanUploadSession(q.db.QueryRow(ctx, query, args...))
+}
+
+func (q *uploadSessions) Select(ctx context.Context) ([]repository.UploadSessionRow, error) {
+	query, args, err := q.selector.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building select query for %s: %w", uploadSessionsTable, err)
+	}
+
+	rows, err := q.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]repository.UploadSessionRow, 0)
+	for rows.Next() {
+		s, err := scanUploadSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning upload session: %w", err)
+		}
+		out = append(out, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (q *uploadSessions) UpdateMany(ctx context.Context) (int64, error) {
+	q.updater = q.updater.Set("updated_at", time.Now().UTC())
+
+	query, args, err := q.updater.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("building update query for %s: %w", uploadSessionsTable, err)
+	}
+
+	tag, err := q.db.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (q *uploadSessions) UpdateOne(ctx context.Context) (repository.UploadSessionRow, error) {
+	q.updater = q.updater.Set("updated_at", time.Now().UTC())
+
+	query, args, err := q.updater.Suffix("RETURNING " + UploadSessionsColumns).ToSql()
+	if err != nil {
+		return repository.UploadSessionRow{}, fmt.Errorf("building update query for %s: %w", uploadSessionsTable, err)
+	}
+
+	return scanUploadSession(q.db.QueryRow(ctx, query, args...))
+}
+
+func (q *uploadSessions) UpdateStatus(status string) repository.UploadSessionsQ {
+	q.updater = q.updater.Set("status", status)
+	return q
+}
+
+func (q *uploadSessions) Delete(ctx context.Context) error {
+	query, args, err := q.deleter.ToSql()
+	if err != nil {
+		return fmt.Errorf("building delete query for %s: %w", uploadSessionsTable, err)
+	}
+
+	_, err = q.db.Exec(ctx, query, args...)
+	return err
+}
+
+func (q *uploadSessions) FilterID(id ...uuid.UUID) repository.UploadSessionsQ {
+	q.selector = q.selector.Where(sq.Eq{"id": id})
+	q.updater = q.updater.Where(sq.Eq{"id": id})
+	q.deleter = q.deleter.Where(sq.Eq{"id": id})
+	return q
+}
+
+func (q *uploadSessions) FilterAccountID(accountID ...uuid.UUID) repository.UploadSessionsQ {
+	q.selector = q.selector.Where(sq.Eq{"account_id": accountID})
+	q.updater = q.updater.Where(sq.Eq{"account_id": accountID})
+	q.deleter = q.deleter.Where(sq.Eq{"account_id": accountID})
+	return q
+}
+
+func (q *uploadSessions) FilterStatus(status ...string) repository.UploadSessionsQ {
+	q.selector = q.selector.Where(sq.Eq{"status": status})
+	q.updater = q.updater.Where(sq.Eq{"status": status})
+	q.deleter = q.deleter.Where(sq.Eq{"status": status})
+	return q
+}
+
+func (q *uploadSessions) FilterExpiresBefore(t time.Time) repository.UploadSessionsQ {
+	q.selector = q.selector.Where(sq.LtOrEq{"expires_at": t})
+	q.updater = q.updater.Where(sq.LtOrEq{"expires_at": t})
+	q.deleter = q.deleter.Where(sq.LtOrEq{"expires_at": t})
+	return q
+}
+
+func (q *uploadSessions) FilterExpiresAfter(t time.Time) repository.UploadSessionsQ {
+	q.selector = q.selector.Where(sq.Gt{"expires_at": t})
+	q.updater = q.updater.Where(sq.Gt{"expires_at": t})
+	q.deleter = q.deleter.Where(sq.Gt{"expires_at": t})
+	return q
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/netbill/profiles-svc/internal/core/errx"
+	"github.com/netbill/profiles-svc/internal/core/models"
+)
+
+type ProfileAvatarRow struct {
+	ID        uuid.UUID         `db:"id"`
+	AccountID uuid.UUID         `db:"account_id"`
+	Key       string            `db:"key"`
+	Variants  map[string]string `db:"variants"`
+	Hash      string            `db:"hash"`
+	Width     int               `db:"width"`
+	Height    int               `db:"height"`
+	CreatedAt time.Time         `db:"created_at"`
+}
+
+func (a ProfileAvatarRow) IsNil() bool {
+	return a.ID == uuid.Nil
+}
+
+func (a ProfileAvatarRow) ToModel() models.ProfileAvatar {
+	return models.ProfileAvatar{
+		ID:        a.ID,
+		AccountID: a.AccountID,
+		Key:       a.Key,
+		Variants:  a.Variants,
+		Hash:      a.Hash,
+		Width:     a.Width,
+		Height:    a.Height,
+		CreatedAt: a.CreatedAt,
+	}
+}
+
+type ProfileAvatarsQ interface {
+	New() ProfileAvatarsQ
+	// Upsert inserts the avatar, an avatar with the same account and hash is moved to the top instead.
+	Upsert(ctx context.Context, input ProfileAvatarRow) (ProfileAvatarRow, error)
+
+	Get(ctx context.Context) (ProfileAvatarRow, error)
+	Select(ctx context.Context) ([]ProfileAvatarRow, error)
+
+	Delete(ctx context.Context) error
+
+	FilterID(id ...uuid.UUID) ProfileAvatarsQ
+	FilterAccountID(accountID uuid.UUID) ProfileAvatarsQ
+	FilterKey(key string) ProfileAvatarsQ
+
+	OrderByCreatedAt(ascend bool) ProfileAvatarsQ
+	// Page limits the selection, a zero limit keeps all rows after the offset.
+	Page(limit, offset uint) ProfileAvatarsQ
+}
+
+func (r *Repository) UpsertProfileAvatar(ctx context.Context, avatar models.ProfileAvatar) (models.ProfileAvatar, error) {
+	row, err := r.profileAvatarsSqlQ().Upsert(ctx, ProfileAvatarRow{
+		ID:        uuid.New(),
+		AccountID: avatar.AccountID,
+		Key:       avatar.Key,
+		Variants:  avatar.Variants,
+		Hash:      avatar.Hash,
+		Width:     avatar.Width,
+		Height:    avatar.Height,
+	})
+	if err != nil {
+		return models.ProfileAvatar{}, fmt.Errorf(
+			"failed to upsert profile avatar %s for account id %s, cause: %w", avatar.Key, avatar.AccountID, err,
+		)
+	}
+
+	return row.ToModel(), nil
+}
+
+func (r *Repository) GetProfileAvatar(ctx context.Context, accountID, avatarID uuid.UUID) (models.ProfileAvatar, error) {
+	row, err := r.profileAvatarsSqlQ().
+		FilterID(avatarID).
+		FilterAccountID(accountID).
+		Get(ctx)
+	switch {
+	case err != nil:
+		return models.ProfileAvatar{}, fmt.Errorf(
+			"failed to get profile avatar %s for account id %s, cause: %w", avatarID, accountID, err,
+		)
+	case row.IsNil():
+		return models.ProfileAvatar{}, errx.ErrorProfileAvatarNotFound.Raise(
+			fmt.Errorf("profile avatar %s for account id %s not found", avatarID, accountID),
+		)
+	}
+
+	return row.ToModel(), nil
+}
+
+func (r *Repository) GetProfileAvatarByKey(ctx context.Context, accountID uuid.UUID, key string) (models.ProfileAvatar, error) {
+	row, err := r.profileAvatarsSqlQ().
+		FilterAccountID(accountID).
+		FilterKey(key).
+		Get(ctx)
+	switch {
+	case err != nil:
+		return models.ProfileAvatar{}, fmt.Errorf(
+			"failed to get profile avatar by key %s for account id %s, cause: %w", key, accountID, err,
+		)
+	case row.IsNil():
+		return models.ProfileAvatar{}, errx.ErrorProfileAvatarNotFound.Raise(
+			fmt.Errorf("profile avatar by key %s for account id %s not found", key, accountID),
+		)
+	}
+
+	return row.ToModel(), nil
+}
+
+// ListProfileAvatars returns the account's avatar history, newest first.
+func (r *Repository) ListProfileAvatars(ctx context.Context, accountID uuid.UUID) ([]models.ProfileAvatar, error) {
+	rows, err := r.profileAvatarsSqlQ().
+		FilterAccountID(accountID).
+		OrderByCreatedAt(false).
+		Select(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to list profile avatars for account id %s, cause: %w", accountID, err,
+		)
+	}
+
+	collection := make([]models.ProfileAvatar, 0, len(rows))
+	for _, row := range rows {
+		collection = append(collection, row.ToModel())
+	}
+
+	return collection, nil
+}
+
+// PruneProfileAvatars deletes all but the newest keep avatars of the account
+// and returns the deleted ones, their objects are left to the caller.
+func (r *Repository) PruneProfileAvatars(
+	ctx context.Context,
+	accountID uuid.UUID,
+	keep uint,
+) ([]models.ProfileAvatar, error) {
+	rows, err := r.profileAvatarsSqlQ().
+		FilterAccountID(accountID).
+		OrderByCreatedAt(false).
+		Page(0, keep).
+		Select(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to select stale profile avatars for account id %s, cause: %w", accountID, err,
+		)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(rows))
+	pruned := make([]models.ProfileAvatar, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+		pruned = append(pruned, row.ToModel())
+	}
+
+	if err = r.profileAvatarsSqlQ().FilterID(ids...).Delete(ctx); err != nil {
+		return nil, fmt.Errorf(
+			"failed to delete %d stale profile avatars for account id %s, cause: %w", len(ids), accountID, err,
+		)
+	}
+
+	return pruned, nil
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/netbill/profiles-svc/internal/core/models"
+)
+
+type ProfileTombstoneRow struct {
+	AccountID uuid.UUID `db:"account_id"`
+	Version   int64     `db:"version"`
+	DeletedAt time.Time `db:"deleted_at"`
+}
+
+func (t ProfileTombstoneRow) IsNil() bool {
+	return t.AccountID == uuid.Nil
+}
+
+func (t ProfileTombstoneRow) ToModel() models.ProfileTombstone {
+	return models.ProfileTombstone{
+		AccountID: t.AccountID,
+		Version:   t.Version,
+		DeletedAt: t.DeletedAt,
+	}
+}
+
+type ProfileTombstonesQ interface {
+	New() ProfileTombstonesQ
+	// Upsert records the deletion at version, a repeated deletion moves deleted_at
+	// forward and never lowers the version.
+	Upsert(ctx context.Context, accountID uuid.UUID, version int64) (ProfileTombstoneRow, error)
+
+	Select(ctx context.Context) ([]ProfileTombstoneRow, error)
+
+	Delete(ctx context.Context) error
+	// DeleteOne deletes the tombstone and returns it, a nil row when there was none.
+	DeleteOne(ctx context.Context) (ProfileTombstoneRow, error)
+
+	FilterAccountID(accountID uuid.UUID) ProfileTombstonesQ
+
+	// Keyset selects up to limit rows ordered by account id, after the given one when it is set.
+	Keyset(limit uint, after *uuid.UUID) ProfileTombstonesQ
+}
+
+func (r *Repository) UpsertProfileTombstone(
+	ctx context.Context,
+	accountID uuid.UUID,
+	version int64,
+) (models.ProfileTombstone, error) {
+	row, err := r.profileTombstonesSqlQ().Upsert(ctx, accountID, version)
+	if err != nil {
+		return models.ProfileTombstone{}, fmt.Errorf(
+			"failed to upsert profile tombstone for account id %s, cause: %w", accountID, err,
+		)
+	}
+
+	return row.ToModel(), nil
+}
+
+// DeleteProfileTombstone returns the deleted tombstone, a zero one when the account had none.
+func (r *Repository) DeleteProfileTombstone(ctx context.Context, accountID uuid.UUID) (models.ProfileTombstone, error) {
+	row, err := r.profileTombstonesSqlQ().FilterAccountID(accountID).DeleteOne(ctx)
+	if err != nil {
+		return models.ProfileTombstone{}, fmt.Errorf(
+			"failed to delete profile tombstone for account id %s, cause: %w", accountID, err,
+		)
+	}
+
+	return row.ToModel(), nil
+}
+
+// ListProfileTombstones returns up to limit tombstones ordered by account id, after the given one when it is set.
+func (r *Repository) ListProfileTombstones(
+	ctx context.Context,
+	after *uuid.UUID,
+	limit uint,
+) ([]models.ProfileTombstone, error) {
+	rows, err := r.profileTombstonesSqlQ().Keyset(limit, after).Select(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list profile tombstones, cause: %w", err)
+	}
+
+	res := make([]models.ProfileTombstone, len(rows))
+	for i, row := range rows {
+		res[i] = row.ToModel()
+	}
+
+	return res, nil
+}
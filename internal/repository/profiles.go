@@ -13,14 +13,17 @@ import (
 )
 
 type ProfileRow struct {
-	AccountID   uuid.UUID `db:"account_id"`
-	Username    string    `db:"username"`
-	Official    bool      `db:"official"`
-	Pseudonym   *string   `db:"pseudonym,omitempty"`
-	Description *string   `db:"description,omitempty"`
-	Avatar      *string   `db:"avatar,omitempty"`
-	CreatedAt   time.Time `db:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at"`
+	AccountID      uuid.UUID         `db:"account_id"`
+	Username       string            `db:"username"`
+	Official       bool              `db:"official"`
+	Pseudonym      *string           `db:"pseudonym,omitempty"`
+	Description    *string           `db:"description,omitempty"`
+	Avatar         *string           `db:"avatar,omitempty"`
+	AvatarVariants map[string]string `db:"avatar_variants"`
+	Banner         *string           `db:"banner,omitempty"`
+	Version        int64             `db:"version"`
+	CreatedAt      time.Time         `db:"created_at"`
+	UpdatedAt      time.Time         `db:"updated_at"`
 }
 
 func (p ProfileRow) IsNil() bool {
@@ -29,14 +32,17 @@ func (p ProfileRow) IsNil() bool {
 
 func (p ProfileRow) ToModel() models.Profile {
 	return models.Profile{
-		AccountID:   p.AccountID,
-		Username:    p.Username,
-		Official:    p.Official,
-		Pseudonym:   p.Pseudonym,
-		Description: p.Description,
-		Avatar:      p.Avatar,
-		CreatedAt:   p.CreatedAt,
-		UpdatedAt:   p.UpdatedAt,
+		AccountID:      p.AccountID,
+		Username:       p.Username,
+		Official:       p.Official,
+		Pseudonym:      p.Pseudonym,
+		Description:    p.Description,
+		Avatar:         p.Avatar,
+		AvatarVariants: p.AvatarVariants,
+		Banner:         p.Banner,
+		Version:        p.Version,
+		CreatedAt:      p.CreatedAt,
+		UpdatedAt:      p.UpdatedAt,
 	}
 }
 
@@ -55,24 +61,42 @@ type ProfilesQ interface {
 	UpdatePseudonym(v *string) ProfilesQ
 	UpdateDescription(v *string) ProfilesQ
 	UpdateAvatar(v *string) ProfilesQ
+	UpdateAvatarVariants(v map[string]string) ProfilesQ
+	UpdateBanner(v *string) ProfilesQ
 
 	Delete(ctx context.Context) error
+	// DeleteOne deletes the profile and returns it, a nil row when there was none.
+	DeleteOne(ctx context.Context) (ProfileRow, error)
 
 	FilterAccountID(accountID ...uuid.UUID) ProfilesQ
 	FilterUsername(username string) ProfilesQ
 	FilterOfficial(official bool) ProfilesQ
-	FilterLikePseudonym(pseudonym string) ProfilesQ
-	FilterLikeUsername(username string) ProfilesQ
+	FilterVersion(version int64) ProfilesQ
+	FilterPseudonymPrefix(prefix string) ProfilesQ
+	FilterUsernamePrefix(prefix string) ProfilesQ
+	FilterSearch(text string) ProfilesQ
 
 	Count(ctx context.Context) (uint, error)
 	Page(limit, offset uint) ProfilesQ
+	Keyset(limit uint, from *ProfileRow, backward bool) ProfilesQ
+
+	OrderBy(field string, ascend bool) ProfilesQ
+	OrderBySearchRank(text string, boostOfficial bool) ProfilesQ
 }
 
-func (r *Repository) InsertProfile(ctx context.Context, accountID uuid.UUID, username string) (models.Profile, error) {
+// InsertProfile creates the profile at version, above the version of its
+// tombstone when the account had a profile before.
+func (r *Repository) InsertProfile(
+	ctx context.Context,
+	accountID uuid.UUID,
+	username string,
+	version int64,
+) (models.Profile, error) {
 	res, err := r.profilesSqlQ().Insert(ctx, ProfileRow{
 		AccountID: accountID,
 		Username:  username,
 		Official:  false,
+		Version:   version,
 	})
 	if err != nil {
 		return models.Profile{}, fmt.Errorf(
@@ -115,6 +139,22 @@ func (r *Repository) GetProfileByUsername(ctx context.Context, username string)
 	return row.ToModel(), nil
 }
 
+func (r *Repository) GetProfilesByAccountIDs(ctx context.Context, accountIDs []uuid.UUID) ([]models.Profile, error) {
+	rows, err := r.profilesSqlQ().FilterAccountID(accountIDs...).Select(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to get profiles by %d account ids, cause: %w", len(accountIDs), err,
+		)
+	}
+
+	collection := make([]models.Profile, 0, len(rows))
+	for _, row := range rows {
+		collection = append(collection, row.ToModel())
+	}
+
+	return collection, nil
+}
+
 func (r *Repository) UpdateProfile(
 	ctx context.Context,
 	accountID uuid.UUID,
@@ -122,9 +162,20 @@ func (r *Repository) UpdateProfile(
 ) (models.Profile, error) {
 	q := r.profilesSqlQ().
 		FilterAccountID(accountID).
-		UpdatePseudonym(input.Pseudonym).
-		UpdateDescription(input.Description).
-		UpdateAvatar(input.GetUpdatedAvatar())
+		UpdateAvatar(input.GetUpdatedAvatar()).
+		UpdateAvatarVariants(input.GetUpdatedAvatarVariants()).
+		UpdateBanner(input.GetUpdatedBanner())
+
+	if input.Pseudonym.Set {
+		q = q.UpdatePseudonym(input.Pseudonym.Value)
+	}
+	if input.Description.Set {
+		q = q.UpdateDescription(input.Description.Value)
+	}
+
+	if input.Version != nil {
+		q = q.FilterVersion(*input.Version)
+	}
 
 	row, err := q.UpdateOne(ctx)
 	switch {
@@ -132,6 +183,8 @@ func (r *Repository) UpdateProfile(
 		return models.Profile{}, fmt.Errorf(
 			"failed to update profile by account id %s, cause: %w", accountID, err,
 		)
+	case row.IsNil() && input.Version != nil:
+		return models.Profile{}, r.versionedUpdateMissed(ctx, accountID, *input.Version)
 	case row.IsNil():
 		return models.Profile{}, errx.ErrorProfileNotFound.Raise(
 			fmt.Errorf("failed to update profile by account id %s, cause: %w", accountID, err),
@@ -141,6 +194,24 @@ func (r *Repository) UpdateProfile(
 	return row.ToModel(), nil
 }
 
+// versionedUpdateMissed tells why an update filtered by version matched no row:
+// the profile does not exist or it is at another version.
+func (r *Repository) versionedUpdateMissed(ctx context.Context, accountID uuid.UUID, version int64) error {
+	row, err := r.profilesSqlQ().FilterAccountID(accountID).Get(ctx)
+	switch {
+	case err != nil:
+		return fmt.Errorf("failed to get profile by account id %s, cause: %w", accountID, err)
+	case row.IsNil():
+		return errx.ErrorProfileNotFound.Raise(
+			fmt.Errorf("profile by account id %s: profile not found", accountID),
+		)
+	}
+
+	return errx.ErrorProfileVersionMismatch.Raise(
+		fmt.Errorf("profile by account id %s was modified, version is %d, expected %d", accountID, row.Version, version),
+	)
+}
+
 func (r *Repository) UpdateProfileUsername(
 	ctx context.Context,
 	accountID uuid.UUID,
@@ -191,10 +262,14 @@ func (r *Repository) UpdateProfileAvatar(
 	ctx context.Context,
 	accountID uuid.UUID,
 	avatarURL string,
+	variants map[string]string,
+	version int64,
 ) (models.Profile, error) {
 	row, err := r.profilesSqlQ().
 		FilterAccountID(accountID).
+		FilterVersion(version).
 		UpdateAvatar(&avatarURL).
+		UpdateAvatarVariants(variants).
 		UpdateOne(ctx)
 	switch {
 	case err != nil:
@@ -202,9 +277,7 @@ func (r *Repository) UpdateProfileAvatar(
 			"failed to update profile avatar by account id %s, cause: %w", accountID, err,
 		)
 	case row.IsNil():
-		return models.Profile{}, errx.ErrorProfileNotFound.Raise(
-			fmt.Errorf("failed to update profile avatar by account id %s, cause: %w", accountID, err),
-		)
+		return models.Profile{}, r.versionedUpdateMissed(ctx, accountID, version)
 	}
 
 	return row.ToModel(), nil
@@ -217,6 +290,7 @@ func (r *Repository) DeleteProfileAvatar(
 	row, err := r.profilesSqlQ().
 		FilterAccountID(accountID).
 		UpdateAvatar(nil).
+		UpdateAvatarVariants(nil).
 		UpdateOne(ctx)
 	switch {
 	case err != nil:
@@ -238,7 +312,7 @@ func (r *Repository) FilterProfilesByUsername(
 	offset uint,
 	limit uint,
 ) (pagi.Page[[]models.Profile], error) {
-	q := r.profilesSqlQ().FilterLikeUsername(prefix)
+	q := r.profilesSqlQ().FilterUsernamePrefix(prefix)
 
 	if limit == 0 {
 		limit = 10
@@ -271,19 +345,43 @@ func (r *Repository) FilterProfilesByUsername(
 	}, nil
 }
 
+func applyProfileFilters(q ProfilesQ, params profile.FilterParams) ProfilesQ {
+	if params.AccountID != nil {
+		q = q.FilterAccountID(*params.AccountID)
+	}
+	if params.Username != nil {
+		q = q.FilterUsername(*params.Username)
+	}
+	if params.Official != nil {
+		q = q.FilterOfficial(*params.Official)
+	}
+	if params.PseudonymPrefix != nil {
+		q = q.FilterPseudonymPrefix(*params.PseudonymPrefix)
+	}
+	if params.UsernamePrefix != nil {
+		q = q.FilterUsernamePrefix(*params.UsernamePrefix)
+	}
+
+	if params.Search != nil {
+		q = q.FilterSearch(*params.Search).OrderBySearchRank(*params.Search, params.BoostOfficial)
+	}
+
+	if len(params.Sort) == 0 && params.Search == nil {
+		return q.OrderBy(profile.SortByCreatedAt, true)
+	}
+	for _, s := range params.Sort {
+		q = q.OrderBy(s.Field, s.Ascend)
+	}
+
+	return q
+}
+
 func (r *Repository) FilterProfiles(
 	ctx context.Context,
 	params profile.FilterParams,
 	limit, offset uint,
 ) (pagi.Page[[]models.Profile], error) {
-	q := r.profilesSqlQ()
-
-	if params.PseudonymPrefix != nil {
-		q = q.FilterLikePseudonym(*params.PseudonymPrefix)
-	}
-	if params.UsernamePrefix != nil {
-		q = q.FilterLikeUsername(*params.UsernamePrefix)
-	}
+	q := applyProfileFilters(r.profilesSqlQ(), params)
 
 	if limit == 0 {
 		limit = 10
@@ -316,6 +414,94 @@ func (r *Repository) FilterProfiles(
 	}, nil
 }
 
-func (r *Repository) DeleteProfile(ctx context.Context, accountID uuid.UUID) error {
-	return r.profilesSqlQ().FilterAccountID(accountID).Delete(ctx)
+func (r *Repository) FilterProfilesByCursor(
+	ctx context.Context,
+	params profile.FilterParams,
+	cursor profile.CursorParams,
+) (profile.CursorPage, error) {
+	limit := cursor.Limit
+	if limit == 0 {
+		limit = 10
+	}
+
+	var from *ProfileRow
+	if cursor.From != nil {
+		from = &ProfileRow{
+			AccountID: cursor.From.AccountID,
+			Username:  cursor.From.Username,
+			Official:  cursor.From.Official,
+			CreatedAt: cursor.From.CreatedAt,
+			UpdatedAt: cursor.From.UpdatedAt,
+		}
+	}
+
+	// one extra row tells whether there is another page in the read direction
+	rows, err := applyProfileFilters(r.profilesSqlQ(), params).
+		Keyset(limit+1, from, cursor.Backward).
+		Select(ctx)
+	if err != nil {
+		return profile.CursorPage{}, fmt.Errorf(
+			"failed to filter profiles by cursor: %w", err,
+		)
+	}
+
+	hasMore := uint(len(rows)) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+
+	collection := make([]models.Profile, len(rows))
+	for i, row := range rows {
+		if cursor.Backward {
+			collection[len(rows)-1-i] = row.ToModel()
+		} else {
+			collection[i] = row.ToModel()
+		}
+	}
+
+	page := profile.CursorPage{Data: collection}
+	if cursor.Backward {
+		page.HasPrev = hasMore
+		page.HasNext = from != nil
+	} else {
+		page.HasNext = hasMore
+		page.HasPrev = from != nil
+	}
+
+	return page, nil
+}
+
+// ListProfilesAfter returns up to limit profiles ordered by account id,
+// after the given one when it is set.
+func (r *Repository) ListProfilesAfter(
+	ctx context.Context,
+	after *uuid.UUID,
+	limit uint,
+) ([]models.Profile, error) {
+	var from *ProfileRow
+	if after != nil {
+		from = &ProfileRow{AccountID: *after}
+	}
+
+	rows, err := r.profilesSqlQ().Keyset(limit, from, false).Select(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list profiles after %v, cause: %w", after, err)
+	}
+
+	res := make([]models.Profile, len(rows))
+	for i, row := range rows {
+		res[i] = row.ToModel()
+	}
+
+	return res, nil
+}
+
+// DeleteProfile returns the version the profile had, 0 when there was no profile.
+func (r *Repository) DeleteProfile(ctx context.Context, accountID uuid.UUID) (int64, error) {
+	row, err := r.profilesSqlQ().FilterAccountID(accountID).DeleteOne(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete profile by account id %s, cause: %w", accountID, err)
+	}
+
+	return row.Version, nil
 }
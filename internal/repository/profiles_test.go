@@ -0,0 +1,273 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/netbill/profiles-svc/internal/core/errx"
+	"github.com/netbill/profiles-svc/internal/core/modules/profile"
+)
+
+// keysetProfiles reads rows in their stored order like the keyset query does,
+// rows before the boundary in reverse when reading backward.
+type keysetProfiles struct {
+	ProfilesQ
+
+	rows []ProfileRow
+
+	limit    uint
+	from     *ProfileRow
+	backward bool
+}
+
+func (q *keysetProfiles) New() ProfilesQ {
+	return &keysetProfiles{rows: q.rows}
+}
+
+func (q *keysetProfiles) OrderBy(string, bool) ProfilesQ {
+	return q
+}
+
+func (q *keysetProfiles) Keyset(limit uint, from *ProfileRow, backward bool) ProfilesQ {
+	q.limit, q.from, q.backward = limit, from, backward
+	return q
+}
+
+func (q *keysetProfiles) Select(context.Context) ([]ProfileRow, error) {
+	rows := q.rows
+	if q.from != nil {
+		i := slices.IndexFunc(rows, func(r ProfileRow) bool { return r.AccountID == q.from.AccountID })
+		if q.backward {
+			rows = rows[:i]
+		} else {
+			rows = rows[i+1:]
+		}
+	}
+	if q.backward {
+		rows = slices.Clone(rows)
+		slices.Reverse(rows)
+	}
+
+	return rows[:min(uint(len(rows)), q.limit)], nil
+}
+
+func TestFilterProfilesByCursor(t *testing.T) {
+	rows := make([]ProfileRow, 5)
+	for i := range rows {
+		rows[i] = ProfileRow{AccountID: uuid.New(), Username: string(rune('a' + i))}
+	}
+	cursorAt := func(i int) *profile.Cursor {
+		return &profile.Cursor{AccountID: rows[i].AccountID, Username: rows[i].Username}
+	}
+
+	tests := []struct {
+		name        string
+		cursor      profile.CursorParams
+		wantUsers   string
+		wantHasNext bool
+		wantHasPrev bool
+	}{
+		{
+			name:        "first page",
+			cursor:      profile.CursorParams{Limit: 2},
+			wantUsers:   "ab",
+			wantHasNext: true,
+		},
+		{
+			name:        "middle page",
+			cursor:      profile.CursorParams{Limit: 2, From: cursorAt(1)},
+			wantUsers:   "cd",
+			wantHasNext: true,
+			wantHasPrev: true,
+		},
+		{
+			name:        "last page",
+			cursor:      profile.CursorParams{Limit: 2, From: cursorAt(2)},
+			wantUsers:   "de",
+			wantHasPrev: true,
+		},
+		{
+			name:        "exact last page",
+			cursor:      profile.CursorParams{Limit: 5},
+			wantUsers:   "abcde",
+			wantHasNext: false,
+		},
+		{
+			name:        "previous page keeps the order",
+			cursor:      profile.CursorParams{Limit: 2, From: cursorAt(3), Backward: true},
+			wantUsers:   "bc",
+			wantHasNext: true,
+			wantHasPrev: true,
+		},
+		{
+			name:        "previous page reaches the start",
+			cursor:      profile.CursorParams{Limit: 2, From: cursorAt(2), Backward: true},
+			wantUsers:   "ab",
+			wantHasNext: true,
+		},
+		{
+			name:        "default limit",
+			cursor:      profile.CursorParams{},
+			wantUsers:   "abcde",
+			wantHasNext: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(nil, &keysetProfiles{rows: rows}, nil, nil, nil)
+
+			page, err := r.FilterProfilesByCursor(context.Background(), profile.FilterParams{}, tt.cursor)
+			if err != nil {
+				t.Fatalf("FilterProfilesByCursor() error = %v", err)
+			}
+
+			users := ""
+			for _, p := range page.Data {
+				users += p.Username
+			}
+			if users != tt.wantUsers {
+				t.Fatalf("page = %q, want %q", users, tt.wantUsers)
+			}
+			if page.HasNext != tt.wantHasNext || page.HasPrev != tt.wantHasPrev {
+				t.Fatalf("HasNext, HasPrev = %v, %v, want %v, %v",
+					page.HasNext, page.HasPrev, tt.wantHasNext, tt.wantHasPrev)
+			}
+		})
+	}
+}
+
+// versionedProfiles updates a stored profile only when it matches the filters,
+// the updated values themselves are ignored.
+type versionedProfiles struct {
+	ProfilesQ
+
+	rows map[uuid.UUID]ProfileRow
+
+	accountID uuid.UUID
+	version   *int64
+}
+
+func (q *versionedProfiles) New() ProfilesQ {
+	return &versionedProfiles{rows: q.rows}
+}
+
+func (q *versionedProfiles) FilterAccountID(accountID ...uuid.UUID) ProfilesQ {
+	q.accountID = accountID[0]
+	return q
+}
+
+func (q *versionedProfiles) FilterVersion(version int64) ProfilesQ {
+	q.version = &version
+	return q
+}
+
+func (q *versionedProfiles) UpdateAvatar(*string) ProfilesQ                   { return q }
+func (q *versionedProfiles) UpdateAvatarVariants(map[string]string) ProfilesQ { return q }
+func (q *versionedProfiles) UpdateBanner(*string) ProfilesQ                   { return q }
+
+func (q *versionedProfiles) Get(context.Context) (ProfileRow, error) {
+	return q.rows[q.accountID], nil
+}
+
+func (q *versionedProfiles) UpdateOne(context.Context) (ProfileRow, error) {
+	row, ok := q.rows[q.accountID]
+	if !ok || (q.version != nil && row.Version != *q.version) {
+		return ProfileRow{}, nil
+	}
+
+	row.Version++
+	q.rows[q.accountID] = row
+	return row, nil
+}
+
+func TestUpdateProfileVersion(t *testing.T) {
+	accountID := uuid.New()
+	version := func(v int64) *int64 { return &v }
+
+	tests := []struct {
+		name        string
+		accountID   uuid.UUID
+		version     *int64
+		wantErr     error
+		wantVersion int64
+	}{
+		{
+			name:        "current version",
+			accountID:   accountID,
+			version:     version(3),
+			wantVersion: 4,
+		},
+		{
+			name:        "no version",
+			accountID:   accountID,
+			wantVersion: 4,
+		},
+		{
+			name:      "stale version",
+			accountID: accountID,
+			version:   version(2),
+			wantErr:   errx.ErrorProfileVersionMismatch,
+		},
+		{
+			name:      "missing profile with a version",
+			accountID: uuid.New(),
+			version:   version(3),
+			wantErr:   errx.ErrorProfileNotFound,
+		},
+		{
+			name:      "missing profile",
+			accountID: uuid.New(),
+			wantErr:   errx.ErrorProfileNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &versionedProfiles{rows: map[uuid.UUID]ProfileRow{
+				accountID: {AccountID: accountID, Username: "alice", Version: 3},
+			}}
+			r := New(nil, q, nil, nil, nil)
+
+			p, err := r.UpdateProfile(context.Background(), tt.accountID, profile.UpdateParams{Version: tt.version})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateProfile() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && p.Version != tt.wantVersion {
+				t.Fatalf("version = %d, want %d", p.Version, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestUpdateProfileAvatarVersion(t *testing.T) {
+	accountID := uuid.New()
+
+	tests := []struct {
+		name      string
+		accountID uuid.UUID
+		version   int64
+		wantErr   error
+	}{
+		{name: "current version", accountID: accountID, version: 3},
+		{name: "stale version", accountID: accountID, version: 2, wantErr: errx.ErrorProfileVersionMismatch},
+		{name: "missing profile", accountID: uuid.New(), version: 3, wantErr: errx.ErrorProfileNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &versionedProfiles{rows: map[uuid.UUID]ProfileRow{
+				accountID: {AccountID: accountID, Username: "alice", Version: 3},
+			}}
+			r := New(nil, q, nil, nil, nil)
+
+			_, err := r.UpdateProfileAvatar(context.Background(), tt.accountID, "avatar-1", nil, tt.version)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateProfileAvatar() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
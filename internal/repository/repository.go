@@ -5,14 +5,26 @@ import (
 )
 
 type Repository struct {
-	profileSql ProfilesQ
+	profileSql       ProfilesQ
+	uploadSessionSql UploadSessionsQ
+	profileAvatarSql ProfileAvatarsQ
+	tombstoneSql     ProfileTombstonesQ
 	Transactioner
 }
 
-func New(Transaction Transactioner, profileSql ProfilesQ) *Repository {
+func New(
+	Transaction Transactioner,
+	profileSql ProfilesQ,
+	uploadSessionSql UploadSessionsQ,
+	profileAvatarSql ProfileAvatarsQ,
+	tombstoneSql ProfileTombstonesQ,
+) *Repository {
 	return &Repository{
-		profileSql:    profileSql,
-		Transactioner: Transaction,
+		profileSql:       profileSql,
+		uploadSessionSql: uploadSessionSql,
+		profileAvatarSql: profileAvatarSql,
+		tombstoneSql:     tombstoneSql,
+		Transactioner:    Transaction,
 	}
 }
 
@@ -20,6 +32,18 @@ func (r *Repository) profilesSqlQ() ProfilesQ {
 	return r.profileSql.New()
 }
 
+func (r *Repository) uploadSessionsSqlQ() UploadSessionsQ {
+	return r.uploadSessionSql.New()
+}
+
+func (r *Repository) profileAvatarsSqlQ() ProfileAvatarsQ {
+	return r.profileAvatarSql.New()
+}
+
+func (r *Repository) profileTombstonesSqlQ() ProfileTombstonesQ {
+	return r.tombstoneSql.New()
+}
+
 type Transactioner interface {
 	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
 }
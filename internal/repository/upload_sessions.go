@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/netbill/profiles-svc/internal/core/errx"
+	"github.com/netbill/profiles-svc/internal/core/models"
+)
+
+type UploadSessionRow struct {
+	ID        uuid.UUID `db:"id"`
+	AccountID uuid.UUID `db:"account_id"`
+	Status    string    `db:"status"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
+
+func (s UploadSessionRow) IsNil() bool {
+	return s.ID == uuid.Nil
+}
+
+func (s UploadSessionRow) ToModel() models.UploadSession {
+	return models.UploadSession{
+		ID:        s.ID,
+		AccountID: s.AccountID,
+		Status:    s.Status,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+		ExpiresAt: s.ExpiresAt,
+	}
+}
+
+type UploadSessionsQ interface {
+	New() UploadSessionsQ
+	Insert(ctx context.Context, input UploadSessionRow) (UploadSessionRow, error)
+
+	Get(ctx context.Context) (UploadSessionRow, error)
+	Select(ctx context.Context) ([]UploadSessionRow, error)
+
+	UpdateMany(ctx context.Context) (int64, error)
+	UpdateOne(ctx context.Context) (UploadSessionRow, error)
+
+	UpdateStatus(status string) UploadSessionsQ
+
+	Delete(ctx context.Context) error
+
+	FilterID(id ...uuid.UUID) UploadSessionsQ
+	FilterAccountID(accountID ...uuid.UUID) UploadSessionsQ
+	FilterStatus(status ...string) UploadSessionsQ
+	FilterExpiresBefore(t time.Time) UploadSessionsQ
+	FilterExpiresAfter(t time.Time) UploadSessionsQ
+}
+
+func (r *Repository) InsertUploadSession(
+	ctx context.Context,
+	accountID, sessionID uuid.UUID,
+	expiresAt time.Time,
+) (models.UploadSession, error) {
+	res, err := r.uploadSessionsSqlQ().Insert(ctx, UploadSessionRow{
+		ID:        sessionID,
+		AccountID: accountID,
+		Status:    models.UploadSessionStatusOpened,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return models.UploadSession{}, fmt.Errorf(
+			"failed to insert upload session %s for account id %s, cause: %w", sessionID, accountID, err,
+		)
+	}
+
+	return res.ToModel(), nil
+}
+
+func (r *Repository) GetUploadSession(
+	ctx context.Context,
+	accountID, sessionID uuid.UUID,
+) (models.UploadSession, error) {
+	row, err := r.uploadSessionsSqlQ().
+		FilterID(sessionID).
+		FilterAccountID(accountID).
+		Get(ctx)
+	switch {
+	case err != nil:
+		return models.UploadSession{}, fmt.Errorf(
+			"failed to get upload session %s for account id %s, cause: %w", sessionID, accountID, err,
+		)
+	case row.IsNil():
+		return models.UploadSession{}, errx.ErrorUploadSessionNotFound.Raise(
+			fmt.Errorf("upload session %s for account id %s not found", sessionID, accountID),
+		)
+	}
+
+	return row.ToModel(), nil
+}
+
+// UpdateUploadSessionStatus moves an active, not yet expired session to status.
+// A session that was already closed, e.g. a confirmed one, is never updated again,
+// so every transition out of an active status happens at most once.
+func (r *Repository) UpdateUploadSessionStatus(
+	ctx context.Context,
+	sessionID uuid.UUID,
+	status string,
+) (models.UploadSession, error) {
+	row, err := r.uploadSessionsSqlQ().
+		FilterID(sessionID).
+		FilterStatus(models.UploadSessionActiveStatuses...).
+		FilterExpiresAfter(time.Now().UTC()).
+		UpdateStatus(status).
+		UpdateOne(ctx)
+	switch {
+	case err != nil:
+		return models.UploadSession{}, fmt.Errorf(
+			"failed to update upload session %s status to %s, cause: %w", sessionID, status, err,
+		)
+	case row.IsNil():
+		return models.UploadSession{}, errx.ErrorUploadSessionClosed.Raise(
+			fmt.Errorf("upload session %s is not active, can not move it to %s", sessionID, status),
+		)
+	}
+
+	return row.ToModel(), nil
+}
+
+// ExpireUploadSessions marks active sessions that expired before the given time,
+// returns the number of expired sessions.
+func (r *Repository) ExpireUploadSessions(ctx context.Context, before time.Time) (int64, error) {
+	n, err := r.uploadSessionsSqlQ().
+		FilterStatus(models.UploadSessionActiveStatuses...).
+		FilterExpiresBefore(before).
+		UpdateStatus(models.UploadSessionStatusExpired).
+		UpdateMany(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire upload sessions before %s, cause: %w", before, err)
+	}
+
+	return n, nil
+}
@@ -1,13 +1,15 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 
+	"github.com/netbill/profiles-svc/internal/core/errx"
 	"github.com/netbill/profiles-svc/internal/rest/contexter"
 	"github.com/netbill/restkit/problems"
 )
 
-func (c *Controller) DeleteUploadProfileAvatar(w http.ResponseWriter, r *http.Request) {
+func (c *Controller) CancelProfileUpdateSession(w http.ResponseWriter, r *http.Request) {
 	initiator, err := contexter.AccountData(r.Context())
 	if err != nil {
 		c.log.WithError(err).Error("failed to get user from context")
@@ -24,14 +26,23 @@ func (c *Controller) DeleteUploadProfileAvatar(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	err = c.core.DeleteUploadProfileAvatarInSession(
+	err = c.core.CancelProfileUpdateSession(
 		r.Context(),
 		initiator.GetAccountID(),
 		uploadFilesData.GetUploadSessionID(),
 	)
 	if err != nil {
-		c.log.WithError(err).Errorf("failed to cancel update avatar")
-		c.responser.RenderErr(w, problems.InternalError())
+		c.log.WithError(err).Error("failed to cancel profile update session")
+		switch {
+		case errors.Is(err, errx.ErrorUploadSessionNotFound):
+			c.responser.RenderErr(w, problems.NotFound("upload session does not exist"))
+		case errors.Is(err, errx.ErrorUploadSessionClosed):
+			c.responser.RenderErr(w, problems.Conflict("upload session is already closed"))
+		case errors.Is(err, errx.ErrorUploadSessionExpired):
+			c.responser.RenderErr(w, problems.Conflict("upload session expired, open a new one"))
+		default:
+			c.responser.RenderErr(w, problems.InternalError())
+		}
 
 		return
 	}
@@ -8,6 +8,7 @@ import (
 	"github.com/netbill/logium"
 	"github.com/netbill/profiles-svc/internal/core/models"
 	"github.com/netbill/profiles-svc/internal/core/modules/profile"
+	"github.com/netbill/profiles-svc/internal/rest/responses"
 	"github.com/netbill/restkit/pagi"
 )
 
@@ -17,9 +18,15 @@ type core interface {
 		params profile.FilterParams,
 		limit, offset uint,
 	) (pagi.Page[[]models.Profile], error)
+	FilterProfileByCursor(
+		ctx context.Context,
+		params profile.FilterParams,
+		cursor profile.CursorParams,
+	) (profile.CursorPage, error)
 
 	GetProfileByAccountID(ctx context.Context, userID uuid.UUID) (models.Profile, error)
 	GetProfileByUsername(ctx context.Context, username string) (models.Profile, error)
+	GetProfilesByAccountIDs(ctx context.Context, accountIDs []uuid.UUID) ([]models.Profile, error)
 
 	UpdateProfileOfficial(ctx context.Context, accountID uuid.UUID, official bool) (models.Profile, error)
 	UpdateProfileUsername(ctx context.Context, accountID uuid.UUID, username string) (models.Profile, error)
@@ -29,10 +36,15 @@ type core interface {
 		ctx context.Context,
 		accountID uuid.UUID,
 	) (models.UpdateProfileMedia, models.Profile, error)
-	DeleteUploadProfileAvatarInSession(
+	DeleteUploadProfileMediaInSession(
 		ctx context.Context,
+		kind models.ProfileMediaKind,
 		accountID, sessionID uuid.UUID,
 	) error
+	CancelProfileUpdateSession(ctx context.Context, accountID, sessionID uuid.UUID) error
+
+	ListProfileAvatars(ctx context.Context, accountID uuid.UUID) ([]models.ProfileAvatar, models.Profile, error)
+	RestoreProfileAvatar(ctx context.Context, accountID, avatarID uuid.UUID, version *int64) (models.Profile, error)
 }
 
 type responser interface {
@@ -45,12 +57,14 @@ type Controller struct {
 
 	core      core
 	responser responser
+	urls      *responses.MediaURLs
 }
 
-func New(log *logium.Logger, responser responser, profile core) *Controller {
+func New(log *logium.Logger, responser responser, profile core, urls *responses.MediaURLs) *Controller {
 	return &Controller{
 		core:      profile,
 		log:       log,
 		responser: responser,
+		urls:      urls,
 	}
 }
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/netbill/profiles-svc/internal/core/errx"
+	"github.com/netbill/profiles-svc/internal/core/models"
+	"github.com/netbill/profiles-svc/internal/rest/contexter"
+	"github.com/netbill/restkit/problems"
+)
+
+func (c *Controller) DeleteUploadProfileAvatar(w http.ResponseWriter, r *http.Request) {
+	c.deleteUploadProfileMedia(w, r, models.ProfileMediaKindAvatar)
+}
+
+func (c *Controller) DeleteUploadProfileBanner(w http.ResponseWriter, r *http.Request) {
+	c.deleteUploadProfileMedia(w, r, models.ProfileMediaKindBanner)
+}
+
+func (c *Controller) deleteUploadProfileMedia(w http.ResponseWriter, r *http.Request, kind models.ProfileMediaKind) {
+	initiator, err := contexter.AccountData(r.Context())
+	if err != nil {
+		c.log.WithError(err).Error("failed to get user from context")
+		c.responser.RenderErr(w, problems.Unauthorized("failed to get user from context"))
+
+		return
+	}
+
+	uploadFilesData, err := contexter.UploadContentData(r.Context())
+	if err != nil {
+		c.log.WithError(err).Error("failed to get upload session id")
+		c.responser.RenderErr(w, problems.Unauthorized("failed to get upload session id"))
+
+		return
+	}
+
+	err = c.core.DeleteUploadProfileMediaInSession(
+		r.Context(),
+		kind,
+		initiator.GetAccountID(),
+		uploadFilesData.GetUploadSessionID(),
+	)
+	if err != nil {
+		c.log.WithError(err).Errorf("failed to cancel update %s", kind)
+		switch {
+		case errors.Is(err, errx.ErrorUploadSessionNotFound):
+			c.responser.RenderErr(w, problems.NotFound("upload session does not exist"))
+		case errors.Is(err, errx.ErrorUploadSessionClosed):
+			c.responser.RenderErr(w, problems.Conflict("upload session is already closed"))
+		case errors.Is(err, errx.ErrorUploadSessionExpired):
+			c.responser.RenderErr(w, problems.Conflict("upload session expired, open a new one"))
+		default:
+			c.responser.RenderErr(w, problems.InternalError())
+		}
+
+		return
+	}
+
+	c.responser.Render(w, 200, nil)
+}
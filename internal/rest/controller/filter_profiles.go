@@ -1,20 +1,48 @@
 package controller
 
 import (
+	"fmt"
 	"net/http"
 	"strings"
 
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
 	"github.com/netbill/profiles-svc/internal/core/modules/profile"
+	"github.com/netbill/profiles-svc/internal/rest/cursor"
 	"github.com/netbill/profiles-svc/internal/rest/responses"
 	"github.com/netbill/restkit/pagi"
 	"github.com/netbill/restkit/problems"
 )
 
+const searchMaxLength = 64
+
 func (c *Controller) FilterProfiles(w http.ResponseWriter, r *http.Request) {
 	q := r.URL.Query()
 	limit, offset := pagi.GetPagination(r)
 
 	filters := profile.FilterParams{}
+	errs := validation.Errors{}
+
+	if accountID := strings.TrimSpace(q.Get("account_id")); accountID != "" {
+		id, err := uuid.Parse(accountID)
+		if err != nil {
+			errs["account_id"] = fmt.Errorf("invalid account id: %s", accountID)
+		}
+		filters.AccountID = &id
+	}
+
+	if username := strings.TrimSpace(q.Get("username")); username != "" {
+		filters.Username = &username
+	}
+
+	switch official := strings.TrimSpace(q.Get("official")); official {
+	case "":
+	case "true", "false":
+		v := official == "true"
+		filters.Official = &v
+	default:
+		errs["official"] = fmt.Errorf("must be true or false")
+	}
 
 	if usernameLike := strings.TrimSpace(q.Get("username_like")); usernameLike != "" {
 		filters.UsernamePrefix = &usernameLike
@@ -24,6 +52,67 @@ func (c *Controller) FilterProfiles(w http.ResponseWriter, r *http.Request) {
 		filters.PseudonymPrefix = &pseudonym
 	}
 
+	if search := strings.TrimSpace(q.Get("q")); search != "" {
+		if err := validation.Validate(search, validation.RuneLength(1, searchMaxLength)); err != nil {
+			errs["q"] = err
+		}
+		if q.Has("cursor") {
+			errs["cursor"] = fmt.Errorf("cursor pagination is not supported together with q")
+		}
+		filters.Search = &search
+	}
+
+	switch boost := strings.TrimSpace(q.Get("boost_official")); boost {
+	case "", "false":
+	case "true":
+		filters.BoostOfficial = true
+	default:
+		errs["boost_official"] = fmt.Errorf("must be true or false")
+	}
+
+	sort, err := parseProfilesSort(q.Get("sort"))
+	if err != nil {
+		errs["sort"] = err
+	}
+	filters.Sort = sort
+
+	if err = errs.Filter(); err != nil {
+		c.log.WithError(err).Errorf("invalid filter profiles request")
+		c.responser.RenderErr(w, problems.BadRequest(err)...)
+
+		return
+	}
+
+	// an empty cursor param requests the first keyset page
+	if q.Has("cursor") {
+		params := profile.CursorParams{Limit: limit}
+
+		if token := q.Get("cursor"); token != "" {
+			from, backward, err := cursor.Decode(token)
+			if err != nil {
+				c.log.WithError(err).Errorf("invalid cursor")
+				c.responser.RenderErr(w, problems.BadRequest(validation.Errors{
+					"cursor": err,
+				})...)
+
+				return
+			}
+
+			params.From = &from
+			params.Backward = backward
+		}
+
+		res, err := c.core.FilterProfileByCursor(r.Context(), filters, params)
+		if err != nil {
+			c.log.WithError(err).Error("failed to filter profiles by cursor")
+			c.responser.RenderErr(w, problems.InternalError())
+			return
+		}
+
+		c.responser.Render(w, http.StatusOK, responses.ProfileCursorCollection(r, res, c.urls))
+		return
+	}
+
 	res, err := c.core.FilterProfile(r.Context(), filters, limit, offset)
 	if err != nil {
 		c.log.WithError(err).Error("failed to filter profiles")
@@ -31,5 +120,54 @@ func (c *Controller) FilterProfiles(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	c.responser.Render(w, http.StatusOK, responses.ProfileCollection(r, res))
+	c.responser.Render(w, http.StatusOK, responses.ProfileCollection(r, res, c.urls))
+}
+
+// parseProfilesSort parses a comma separated list of sort fields,
+// a leading "-" sorts the field in descending order, e.g. "-official,username".
+func parseProfilesSort(raw string) ([]pagi.SortField, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(raw, ",")
+	res := make([]pagi.SortField, 0, len(parts))
+	seen := make(map[string]struct{}, len(parts))
+
+	for _, part := range parts {
+		field := pagi.SortField{Ascend: true, Field: strings.TrimSpace(part)}
+		if strings.HasPrefix(field.Field, "-") {
+			field.Ascend = false
+			field.Field = strings.TrimSpace(strings.TrimPrefix(field.Field, "-"))
+		}
+
+		if err := validation.Validate(
+			field.Field,
+			validation.Required,
+			validation.In(toAny(profile.SortFields)...).Error(fmt.Sprintf(
+				"must be one of: %s", strings.Join(profile.SortFields, ", "),
+			)),
+		); err != nil {
+			return nil, fmt.Errorf("sort field %q: %w", field.Field, err)
+		}
+
+		if _, ok := seen[field.Field]; ok {
+			return nil, fmt.Errorf("sort field %q: is repeated", field.Field)
+		}
+		seen[field.Field] = struct{}{}
+
+		res = append(res, field)
+	}
+
+	return res, nil
+}
+
+func toAny[T any](values []T) []any {
+	res := make([]any, len(values))
+	for i, v := range values {
+		res[i] = v
+	}
+
+	return res
 }
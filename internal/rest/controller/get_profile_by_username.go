@@ -26,5 +26,6 @@ func (c *Controller) GetProfileByUsername(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	c.responser.Render(w, http.StatusOK, responses.Profile(res))
+	w.Header().Set("ETag", responses.ProfileETag(res))
+	c.responser.Render(w, http.StatusOK, responses.Profile(r.Context(), res, c.urls))
 }
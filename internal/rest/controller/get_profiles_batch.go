@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/netbill/profiles-svc/internal/rest/requests"
+	"github.com/netbill/profiles-svc/internal/rest/responses"
+	"github.com/netbill/restkit/problems"
+)
+
+func (c *Controller) GetProfilesBatch(w http.ResponseWriter, r *http.Request) {
+	req, err := requests.ProfilesBatch(r)
+	if err != nil {
+		c.log.WithError(err).Errorf("invalid profiles batch request")
+		c.responser.RenderErr(w, problems.BadRequest(err)...)
+
+		return
+	}
+
+	res, err := c.core.GetProfilesByAccountIDs(r.Context(), req.Data.Attributes.AccountIds)
+	if err != nil {
+		c.log.WithError(err).Errorf("failed to get profiles by account ids")
+		c.responser.RenderErr(w, problems.InternalError())
+
+		return
+	}
+
+	found := make(map[uuid.UUID]struct{}, len(res))
+	for _, p := range res {
+		found[p.AccountID] = struct{}{}
+	}
+
+	missing := make([]uuid.UUID, 0)
+	for _, id := range req.Data.Attributes.AccountIds {
+		if _, ok := found[id]; ok {
+			continue
+		}
+		found[id] = struct{}{}
+		missing = append(missing, id)
+	}
+
+	c.responser.Render(w, http.StatusOK, responses.ProfilesBatch(r, res, missing, c.urls))
+}
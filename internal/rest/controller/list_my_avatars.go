@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/netbill/profiles-svc/internal/core/errx"
+	"github.com/netbill/profiles-svc/internal/rest/contexter"
+	"github.com/netbill/profiles-svc/internal/rest/responses"
+	"github.com/netbill/restkit/problems"
+)
+
+func (c *Controller) ListMyAvatars(w http.ResponseWriter, r *http.Request) {
+	initiator, err := contexter.AccountData(r.Context())
+	if err != nil {
+		c.log.WithError(err).Error("failed to get account from context")
+		c.responser.RenderErr(w, problems.Unauthorized("failed to get account from context"))
+
+		return
+	}
+
+	avatars, profile, err := c.core.ListProfileAvatars(r.Context(), initiator.GetAccountID())
+	if err != nil {
+		c.log.WithError(err).Errorf("failed to list profile avatars")
+		switch {
+		case errors.Is(err, errx.ErrorProfileNotFound):
+			c.responser.RenderErr(w, problems.Unauthorized("profile for user does not exist"))
+		default:
+			c.responser.RenderErr(w, problems.InternalError())
+		}
+
+		return
+	}
+
+	resp, err := responses.ProfileAvatarsCollection(r.Context(), avatars, profile, c.urls)
+	if err != nil {
+		c.log.WithError(err).Errorf("failed to build profile avatars response")
+		c.responser.RenderErr(w, problems.InternalError())
+
+		return
+	}
+
+	c.responser.Render(w, http.StatusOK, resp)
+}
@@ -35,5 +35,5 @@ func (c *Controller) OenProfileUpdateSession(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	c.responser.Render(w, 200, responses.UpdateProfileSession(media, profile))
+	c.responser.Render(w, 200, responses.UpdateProfileSession(r.Context(), media, profile, c.urls))
 }
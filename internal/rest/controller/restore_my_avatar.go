@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+	"github.com/netbill/profiles-svc/internal/core/errx"
+	"github.com/netbill/profiles-svc/internal/rest/contexter"
+	"github.com/netbill/profiles-svc/internal/rest/requests"
+	"github.com/netbill/profiles-svc/internal/rest/responses"
+	"github.com/netbill/restkit/problems"
+)
+
+func (c *Controller) RestoreMyAvatar(w http.ResponseWriter, r *http.Request) {
+	initiator, err := contexter.AccountData(r.Context())
+	if err != nil {
+		c.log.WithError(err).Error("failed to get account from context")
+		c.responser.RenderErr(w, problems.Unauthorized("failed to get account from context"))
+
+		return
+	}
+
+	avatarID, err := uuid.Parse(chi.URLParam(r, "avatar_id"))
+	if err != nil {
+		c.log.WithError(err).Errorf("invalid avatar id")
+		c.responser.RenderErr(w, problems.BadRequest(validation.Errors{
+			"query": fmt.Errorf("invalid avatar id: %s", chi.URLParam(r, "avatar_id")),
+		})...)
+
+		return
+	}
+
+	version, err := requests.IfMatchVersion(r)
+	if err != nil {
+		c.log.WithError(err).Errorf("invalid If-Match header")
+		c.responser.RenderErr(w, problems.BadRequest(err)...)
+
+		return
+	}
+
+	res, err := c.core.RestoreProfileAvatar(r.Context(), initiator.GetAccountID(), avatarID, version)
+	if err != nil {
+		c.log.WithError(err).Errorf("failed to restore profile avatar")
+		switch {
+		case errors.Is(err, errx.ErrorProfileAvatarNotFound):
+			c.responser.RenderErr(w, problems.NotFound("avatar is not in the avatar history"))
+		case errors.Is(err, errx.ErrorProfileNotFound):
+			c.responser.RenderErr(w, problems.Unauthorized("profile for user does not exist"))
+		case errors.Is(err, errx.ErrorProfileVersionMismatch):
+			c.responser.RenderErr(w, problems.PreconditionFailed("profile was modified, fetch it again and retry"))
+		default:
+			c.responser.RenderErr(w, problems.InternalError())
+		}
+
+		return
+	}
+
+	w.Header().Set("ETag", responses.ProfileETag(res))
+	c.responser.Render(w, http.StatusOK, responses.Profile(r.Context(), res, c.urls))
+}
@@ -7,6 +7,7 @@ import (
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/netbill/profiles-svc/internal/core/errx"
+	"github.com/netbill/profiles-svc/internal/core/models"
 	"github.com/netbill/profiles-svc/internal/core/modules/profile"
 	"github.com/netbill/profiles-svc/internal/rest/contexter"
 	"github.com/netbill/restkit/problems"
@@ -45,6 +46,14 @@ func (c *Controller) ConfirmUpdateMyProfile(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	version, err := requests.IfMatchVersion(r)
+	if err != nil {
+		c.log.WithError(err).Errorf("invalid If-Match header")
+		c.responser.RenderErr(w, problems.BadRequest(err)...)
+
+		return
+	}
+
 	uploadData, err := contexter.UploadContentData(r.Context())
 	if err != nil {
 		c.log.WithError(err).Error("failed to get upload session id")
@@ -57,11 +66,21 @@ func (c *Controller) ConfirmUpdateMyProfile(w http.ResponseWriter, r *http.Reque
 		r.Context(),
 		initiator.GetAccountID(),
 		profile.UpdateParams{
-			Pseudonym:   req.Data.Attributes.Pseudonym,
-			Description: req.Data.Attributes.Description,
+			Pseudonym: profile.UpdateField[string]{
+				Set:   req.Data.Attributes.Pseudonym.IsSet(),
+				Value: req.Data.Attributes.Pseudonym.Get(),
+			},
+			Description: profile.UpdateField[string]{
+				Set:   req.Data.Attributes.Description.IsSet(),
+				Value: req.Data.Attributes.Description.Get(),
+			},
+			Version: version,
 			Media: profile.UpdateMediaParams{
 				UploadSessionID: uploadData.GetUploadSessionID(),
-				DeleteAvatar:    req.Data.Attributes.DeleteAvatar,
+				Delete: map[models.ProfileMediaKind]bool{
+					models.ProfileMediaKindAvatar: req.Data.Attributes.DeleteAvatar,
+					models.ProfileMediaKindBanner: req.Data.Attributes.GetDeleteBanner(),
+				},
 			},
 		},
 	)
@@ -70,12 +89,52 @@ func (c *Controller) ConfirmUpdateMyProfile(w http.ResponseWriter, r *http.Reque
 		switch {
 		case errors.Is(err, errx.ErrorProfileNotFound):
 			c.responser.RenderErr(w, problems.Unauthorized("profile for user does not exist"))
+		case errors.Is(err, errx.ErrorProfileVersionMismatch):
+			c.responser.RenderErr(w, problems.PreconditionFailed("profile was modified, fetch it again and retry"))
+		case errors.Is(err, errx.ErrorUploadSessionNotFound):
+			c.responser.RenderErr(w, problems.NotFound("upload session does not exist"))
+		case errors.Is(err, errx.ErrorUploadSessionClosed):
+			c.responser.RenderErr(w, problems.Conflict("upload session is already closed"))
+		case errors.Is(err, errx.ErrorUploadSessionExpired):
+			c.responser.RenderErr(w, problems.Conflict("upload session expired, open a new one"))
+		case errors.Is(err, errx.ErrorProfilePseudonymTooLong),
+			errors.Is(err, errx.ErrorProfilePseudonymNotAllowed):
+			c.responser.RenderErr(w, problems.BadRequest(validation.Errors{
+				"data/attributes/pseudonym": err,
+			})...)
+		case errors.Is(err, errx.ErrorProfileDescriptionTooLong),
+			errors.Is(err, errx.ErrorProfileDescriptionNotAllowed):
+			c.responser.RenderErr(w, problems.BadRequest(validation.Errors{
+				"data/attributes/description": err,
+			})...)
 		case errors.Is(err, errx.ErrorProfileAvatarContentFormatIsNotAllowed),
 			errors.Is(err, errx.ErrorProfileAvatarTooLarge),
+			errors.Is(err, errx.ErrorProfileAvatarTruncated),
+			errors.Is(err, errx.ErrorProfileAvatarCorrupted),
+			errors.Is(err, errx.ErrorProfileAvatarTooManyPixels),
+			errors.Is(err, errx.ErrorProfileAvatarTooManyFrames),
+			errors.Is(err, errx.ErrorProfileAvatarAspectRatioIsNotAllowed),
+			errors.Is(err, errx.ErrorProfileAvatarRejected),
 			errors.Is(err, errx.ErrorProfileAvatarContentTypeIsNotAllowed):
 			c.responser.RenderErr(w, problems.BadRequest(validation.Errors{
 				"avatar": fmt.Errorf(err.Error()),
 			})...)
+		case errors.Is(err, errx.ErrorProfileBannerContentFormatIsNotAllowed),
+			errors.Is(err, errx.ErrorProfileBannerTooLarge),
+			errors.Is(err, errx.ErrorProfileBannerTruncated),
+			errors.Is(err, errx.ErrorProfileBannerCorrupted),
+			errors.Is(err, errx.ErrorProfileBannerTooManyPixels),
+			errors.Is(err, errx.ErrorProfileBannerTooManyFrames),
+			errors.Is(err, errx.ErrorProfileBannerAspectRatioIsNotAllowed),
+			errors.Is(err, errx.ErrorProfileBannerRejected),
+			errors.Is(err, errx.ErrorProfileBannerContentTypeIsNotAllowed):
+			c.responser.RenderErr(w, problems.BadRequest(validation.Errors{
+				"banner": err,
+			})...)
+		case errors.Is(err, errx.ErrorProfileAvatarQuarantined):
+			c.responser.RenderErr(w, problems.Conflict("avatar is held for moderation review"))
+		case errors.Is(err, errx.ErrorProfileBannerQuarantined):
+			c.responser.RenderErr(w, problems.Conflict("banner is held for moderation review"))
 		default:
 			c.responser.RenderErr(w, problems.InternalError())
 		}
@@ -83,5 +142,6 @@ func (c *Controller) ConfirmUpdateMyProfile(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	c.responser.Render(w, http.StatusOK, responses.Profile(res))
+	w.Header().Set("ETag", responses.ProfileETag(res))
+	c.responser.Render(w, http.StatusOK, responses.Profile(r.Context(), res, c.urls))
 }
@@ -32,5 +32,5 @@ func (c *Controller) UpdateProfileOfficial(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	c.responser.Render(w, http.StatusOK, responses.Profile(res))
+	c.responser.Render(w, http.StatusOK, responses.Profile(r.Context(), res, c.urls))
 }
@@ -0,0 +1,60 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/netbill/profiles-svc/internal/core/modules/profile"
+)
+
+// token is the content of an opaque cursor, clients must not rely on its layout.
+type token struct {
+	AccountID uuid.UUID `json:"id"`
+	Username  string    `json:"u"`
+	Official  bool      `json:"o,omitempty"`
+	CreatedAt time.Time `json:"ca"`
+	UpdatedAt time.Time `json:"ua"`
+	Backward  bool      `json:"b,omitempty"`
+}
+
+func Encode(c profile.Cursor, backward bool) string {
+	raw, err := json.Marshal(token{
+		AccountID: c.AccountID,
+		Username:  c.Username,
+		Official:  c.Official,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+		Backward:  backward,
+	})
+	if err != nil {
+		panic(fmt.Errorf("failed to marshal cursor: %w", err))
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func Decode(s string) (c profile.Cursor, backward bool, err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return profile.Cursor{}, false, fmt.Errorf("invalid cursor encoding")
+	}
+
+	var t token
+	if err = json.Unmarshal(raw, &t); err != nil {
+		return profile.Cursor{}, false, fmt.Errorf("invalid cursor payload")
+	}
+	if t.AccountID == uuid.Nil || t.CreatedAt.IsZero() || t.UpdatedAt.IsZero() {
+		return profile.Cursor{}, false, fmt.Errorf("invalid cursor payload")
+	}
+
+	return profile.Cursor{
+		AccountID: t.AccountID,
+		Username:  t.Username,
+		Official:  t.Official,
+		CreatedAt: t.CreatedAt,
+		UpdatedAt: t.UpdatedAt,
+	}, t.Backward, nil
+}
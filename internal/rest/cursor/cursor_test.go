@@ -0,0 +1,55 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/netbill/profiles-svc/internal/core/modules/profile"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	c := profile.Cursor{
+		AccountID: uuid.New(),
+		Username:  "alice",
+		Official:  true,
+		CreatedAt: time.Date(2026, 10, 18, 12, 0, 0, 123456789, time.UTC),
+		UpdatedAt: time.Date(2026, 10, 18, 13, 0, 0, 0, time.UTC),
+	}
+
+	for _, backward := range []bool{false, true} {
+		got, gotBackward, err := Decode(Encode(c, backward))
+		if err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		if got != c || gotBackward != backward {
+			t.Fatalf("Decode() = %+v, %v, want %+v, %v", got, gotBackward, c, backward)
+		}
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	encode := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "empty", token: ""},
+		{name: "not base64", token: "!!!"},
+		{name: "not json", token: encode("cursor")},
+		{name: "no account id", token: encode(`{"ca":"2026-10-18T12:00:00Z","ua":"2026-10-18T12:00:00Z"}`)},
+		{name: "no timestamps", token: encode(`{"id":"` + uuid.NewString() + `"}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := Decode(tt.token); err == nil {
+				t.Fatalf("Decode(%q) error = nil, want error", tt.token)
+			}
+		})
+	}
+}
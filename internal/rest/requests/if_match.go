@@ -0,0 +1,35 @@
+package requests
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// IfMatchVersion parses the If-Match header into a profile version,
+// it returns nil when the header is absent or is "*".
+func IfMatchVersion(r *http.Request) (*int64, error) {
+	raw := strings.TrimSpace(r.Header.Get("If-Match"))
+	if raw == "" || raw == "*" {
+		return nil, nil
+	}
+
+	unquoted, err := strconv.Unquote(raw)
+	if err != nil {
+		return nil, validation.Errors{
+			"If-Match": fmt.Errorf("expected a single strong etag"),
+		}
+	}
+
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil {
+		return nil, validation.Errors{
+			"If-Match": fmt.Errorf("unknown etag %s", raw),
+		}
+	}
+
+	return &version, nil
+}
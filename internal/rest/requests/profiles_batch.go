@@ -0,0 +1,30 @@
+package requests
+
+import (
+	"encoding/json"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/netbill/profiles-svc/resources"
+	"github.com/netbill/restkit"
+)
+
+const profilesBatchMaxSize = 200
+
+func ProfilesBatch(r *http.Request) (req resources.ProfilesBatch, err error) {
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		err = restkit.NewDecodeError("body", err)
+		return
+	}
+
+	errs := validation.Errors{
+		"data/type": validation.Validate(req.Data.Type, validation.Required, validation.In("profiles_batch")),
+		"data/attributes/account_ids": validation.Validate(
+			req.Data.Attributes.AccountIds,
+			validation.Required,
+			validation.Length(1, profilesBatchMaxSize),
+		),
+	}
+
+	return req, errs.Filter()
+}
@@ -0,0 +1,128 @@
+package responses
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/netbill/logium"
+)
+
+const (
+	// MediaURLModeKey keeps stored keys as they are.
+	MediaURLModeKey = "key"
+	// MediaURLModePublic prefixes keys with a public base URL, e.g. a CDN.
+	MediaURLModePublic = "public"
+	// MediaURLModePresign returns presigned GET URLs that expire after a TTL.
+	MediaURLModePresign = "presign"
+)
+
+type presigner interface {
+	PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+type MediaURLsConfig struct {
+	Mode          string
+	PublicBaseURL string
+	TTL           time.Duration
+}
+
+// MediaURLs resolves stored media keys into URLs clients can fetch,
+// so that the bucket does not have to be public.
+type MediaURLs struct {
+	log       *logium.Logger
+	presigner presigner
+
+	mode          string
+	publicBaseURL string
+	ttl           time.Duration
+}
+
+func NewMediaURLs(log *logium.Logger, presigner presigner, cfg MediaURLsConfig) (*MediaURLs, error) {
+	switch cfg.Mode {
+	case "", MediaURLModeKey:
+		cfg.Mode = MediaURLModeKey
+	case MediaURLModePublic:
+		if _, err := url.ParseRequestURI(cfg.PublicBaseURL); err != nil {
+			return nil, fmt.Errorf("invalid media public base url %q: %w", cfg.PublicBaseURL, err)
+		}
+	case MediaURLModePresign:
+		if cfg.TTL <= 0 {
+			return nil, fmt.Errorf("media presign ttl must be positive")
+		}
+	default:
+		return nil, fmt.Errorf("unknown media url mode %q", cfg.Mode)
+	}
+
+	return &MediaURLs{
+		log:           log,
+		presigner:     presigner,
+		mode:          cfg.Mode,
+		publicBaseURL: strings.TrimSuffix(cfg.PublicBaseURL, "/"),
+		ttl:           cfg.TTL,
+	}, nil
+}
+
+// URL resolves a stored key, a nil resolver returns the key unchanged.
+// The presign mode signs with ctx, the context of the request being answered.
+func (u *MediaURLs) URL(ctx context.Context, key string) (string, error) {
+	if u == nil {
+		return key, nil
+	}
+
+	switch u.mode {
+	case MediaURLModePublic:
+		return u.publicBaseURL + "/" + strings.TrimPrefix(key, "/"), nil
+	case MediaURLModePresign:
+		link, err := u.presigner.PresignGet(ctx, key, u.ttl)
+		if err != nil {
+			return "", fmt.Errorf("failed to presign media url for %s: %w", key, err)
+		}
+
+		return link, nil
+	default:
+		return key, nil
+	}
+}
+
+// optionalURL resolves an optional key, a key that fails to resolve is logged
+// and left out of the response rather than sent in place of a URL.
+func (u *MediaURLs) optionalURL(ctx context.Context, key *string) *string {
+	if key == nil {
+		return nil
+	}
+
+	link, err := u.URL(ctx, *key)
+	if err != nil {
+		u.log.WithError(err).Error("leaving media url out of the response")
+		return nil
+	}
+
+	return &link
+}
+
+// variantURLs resolves variant keys, variants that fail to resolve are logged and left out.
+func (u *MediaURLs) variantURLs(ctx context.Context, variants map[string]string) map[string]string {
+	if len(variants) == 0 {
+		return nil
+	}
+
+	out := make(map[string]string, len(variants))
+	for name, key := range variants {
+		link, err := u.URL(ctx, key)
+		if err != nil {
+			u.log.WithError(err).Errorf("leaving media variant %s url out of the response", name)
+			continue
+		}
+
+		out[name] = link
+	}
+
+	if len(out) == 0 {
+		return nil
+	}
+
+	return out
+}
@@ -0,0 +1,74 @@
+package responses
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/netbill/logium"
+)
+
+type ctxKey struct{}
+
+type fakePresigner struct {
+	failKeys map[string]bool
+}
+
+func (p fakePresigner) PresignGet(ctx context.Context, key string, _ time.Duration) (string, error) {
+	if p.failKeys[key] {
+		return "", errors.New("presign failed")
+	}
+
+	return "https://signed/" + key + "?req=" + ctx.Value(ctxKey{}).(string), nil
+}
+
+func TestMediaURLs(t *testing.T) {
+	ctx := context.WithValue(context.Background(), ctxKey{}, "r1")
+
+	urls, err := NewMediaURLs(logium.New(), fakePresigner{failKeys: map[string]bool{"bad": true}}, MediaURLsConfig{
+		Mode: MediaURLModePresign,
+		TTL:  time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("NewMediaURLs() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		key     string
+		want    string
+		wantErr bool
+	}{
+		{name: "signed with request context", key: "profile/avatar/a", want: "https://signed/profile/avatar/a?req=r1"},
+		{name: "presign failure", key: "bad", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := urls.URL(ctx, tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("URL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("URL() = %q, want %q", got, tt.want)
+			}
+
+			opt := urls.optionalURL(ctx, &tt.key)
+			if tt.wantErr {
+				if opt != nil {
+					t.Fatalf("optionalURL() = %q, want nil", *opt)
+				}
+				return
+			}
+			if opt == nil || *opt != tt.want {
+				t.Fatalf("optionalURL() = %v, want %q", opt, tt.want)
+			}
+		})
+	}
+
+	variants := urls.variantURLs(ctx, map[string]string{"64": "v64", "128": "bad"})
+	if len(variants) != 1 || variants["64"] != "https://signed/v64?req=r1" {
+		t.Fatalf("variantURLs() = %v, want only the 64 variant", variants)
+	}
+}
@@ -1,14 +1,19 @@
 package responses
 
 import (
+	"context"
 	"net/http"
+	"strconv"
 
+	"github.com/google/uuid"
 	"github.com/netbill/profiles-svc/internal/core/models"
+	"github.com/netbill/profiles-svc/internal/core/modules/profile"
+	"github.com/netbill/profiles-svc/internal/rest/cursor"
 	"github.com/netbill/profiles-svc/resources"
 	"github.com/netbill/restkit/pagi"
 )
 
-func Profile(m models.Profile) resources.Profile {
+func Profile(ctx context.Context, m models.Profile, urls *MediaURLs) resources.Profile {
 	resp := resources.Profile{
 		Data: resources.ProfileData{
 			Id:   m.AccountID,
@@ -18,21 +23,26 @@ func Profile(m models.Profile) resources.Profile {
 				Pseudonym:   m.Pseudonym,
 				Description: m.Description,
 				Official:    m.Official,
-				Avatar:      m.Avatar,
+				Avatar:      urls.optionalURL(ctx, m.Avatar),
+				Banner:      urls.optionalURL(ctx, m.Banner),
 				UpdatedAt:   m.UpdatedAt,
 				CreatedAt:   m.CreatedAt,
 			},
 		},
 	}
 
+	if variants := urls.variantURLs(ctx, m.AvatarVariants); len(variants) > 0 {
+		resp.Data.Attributes.SetAvatarVariants(variants)
+	}
+
 	return resp
 }
 
-func ProfileCollection(r *http.Request, m pagi.Page[[]models.Profile]) resources.ProfilesCollection {
+func ProfileCollection(r *http.Request, m pagi.Page[[]models.Profile], urls *MediaURLs) resources.ProfilesCollection {
 	data := make([]resources.ProfileData, len(m.Data))
 
 	for i, profile := range m.Data {
-		data[i] = Profile(profile).Data
+		data[i] = Profile(r.Context(), profile, urls).Data
 	}
 
 	links := pagi.BuildPageLinks(r, m.Page, m.Size, m.Total)
@@ -49,15 +59,87 @@ func ProfileCollection(r *http.Request, m pagi.Page[[]models.Profile]) resources
 	}
 }
 
-func UpdateProfileSession(uploadLinks models.UpdateProfileMedia, profile models.Profile) resources.UpdateProfileSession {
+func ProfileCursorCollection(r *http.Request, m profile.CursorPage, urls *MediaURLs) resources.ProfilesCollection {
+	data := make([]resources.ProfileData, len(m.Data))
+
+	for i, p := range m.Data {
+		data[i] = Profile(r.Context(), p, urls).Data
+	}
+
+	first := buildURLWithCursor(r, "")
+	links := resources.PaginationData{
+		Self:  r.URL.String(),
+		First: &first,
+	}
+
+	if len(m.Data) > 0 {
+		if m.HasNext {
+			last := m.Data[len(m.Data)-1]
+			next := buildURLWithCursor(r, cursor.Encode(profile.NewCursor(last), false))
+			links.Next = &next
+		}
+		if m.HasPrev {
+			prev := buildURLWithCursor(r, cursor.Encode(profile.NewCursor(m.Data[0]), true))
+			links.Prev = &prev
+		}
+	}
+
+	return resources.ProfilesCollection{
+		Data:  data,
+		Links: links,
+	}
+}
+
+func buildURLWithCursor(r *http.Request, token string) string {
+	u := *r.URL
+	q := u.Query()
+
+	q.Set("cursor", token)
+	q.Del("page")
+
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+func ProfilesBatch(
+	r *http.Request,
+	profiles []models.Profile,
+	missing []uuid.UUID,
+	urls *MediaURLs,
+) resources.ProfilesCollection {
+	data := make([]resources.ProfileData, len(profiles))
+
+	for i, profile := range profiles {
+		data[i] = Profile(r.Context(), profile, urls).Data
+	}
+
+	return resources.ProfilesCollection{
+		Data: data,
+		Links: resources.PaginationData{
+			Self: r.URL.String(),
+		},
+		Meta: &resources.ProfilesCollectionMeta{
+			MissingAccountIds: missing,
+		},
+	}
+}
+
+func UpdateProfileSession(
+	ctx context.Context,
+	uploadLinks models.UpdateProfileMedia,
+	profile models.Profile,
+	urls *MediaURLs,
+) resources.UpdateProfileSession {
 	return resources.UpdateProfileSession{
 		Data: resources.UpdateProfileSessionData{
 			Id:   uploadLinks.UploadSessionID,
 			Type: "update_profile_session",
 			Attributes: resources.UpdateProfileSessionDataAttributes{
-				UploadToken: uploadLinks.UploadToken,
-				UploadUrl:   uploadLinks.Links.UploadURL,
-				GetUrl:      uploadLinks.Links.GetURL,
+				UploadToken:     uploadLinks.UploadToken,
+				UploadUrl:       uploadLinks.Links[models.ProfileMediaKindAvatar].UploadURL,
+				GetUrl:          uploadLinks.Links[models.ProfileMediaKindAvatar].GetURL,
+				BannerUploadUrl: uploadLinks.Links[models.ProfileMediaKindBanner].UploadURL,
+				BannerGetUrl:    uploadLinks.Links[models.ProfileMediaKindBanner].GetURL,
 			},
 			Relationships: resources.UpdateProfileSessionDataRelationships{
 				Profile: &resources.UpdateProfileSessionDataRelationshipsProfile{
@@ -69,7 +151,12 @@ func UpdateProfileSession(uploadLinks models.UpdateProfileMedia, profile models.
 			},
 		},
 		Included: []resources.ProfileData{
-			Profile(profile).Data,
+			Profile(ctx, profile, urls).Data,
 		},
 	}
 }
+
+// ProfileETag is a strong etag of the profile representation, clients send it back in If-Match.
+func ProfileETag(m models.Profile) string {
+	return strconv.Quote(strconv.FormatInt(m.Version, 10))
+}
@@ -0,0 +1,63 @@
+package responses
+
+import (
+	"context"
+
+	"github.com/netbill/profiles-svc/internal/core/models"
+	"github.com/netbill/profiles-svc/resources"
+)
+
+func ProfileAvatar(
+	ctx context.Context,
+	m models.ProfileAvatar,
+	profile models.Profile,
+	urls *MediaURLs,
+) (resources.ProfileAvatarData, error) {
+	url, err := urls.URL(ctx, m.Key)
+	if err != nil {
+		return resources.ProfileAvatarData{}, err
+	}
+
+	resp := resources.ProfileAvatarData{
+		Id:   m.ID,
+		Type: "profile_avatar",
+		Attributes: resources.ProfileAvatarAttributes{
+			Key:       m.Key,
+			Url:       url,
+			Hash:      m.Hash,
+			Width:     int32(m.Width),
+			Height:    int32(m.Height),
+			Current:   profile.Avatar != nil && *profile.Avatar == m.Key,
+			CreatedAt: m.CreatedAt,
+		},
+	}
+
+	if variants := urls.variantURLs(ctx, m.Variants); len(variants) > 0 {
+		resp.Attributes.SetVariants(variants)
+	}
+
+	return resp, nil
+}
+
+// ProfileAvatarsCollection fails if the url of an avatar can not be resolved,
+// the url is a required attribute of every avatar.
+func ProfileAvatarsCollection(
+	ctx context.Context,
+	avatars []models.ProfileAvatar,
+	profile models.Profile,
+	urls *MediaURLs,
+) (resources.ProfileAvatarsCollection, error) {
+	data := make([]resources.ProfileAvatarData, len(avatars))
+
+	for i, avatar := range avatars {
+		var err error
+		data[i], err = ProfileAvatar(ctx, avatar, profile, urls)
+		if err != nil {
+			return resources.ProfileAvatarsCollection{}, err
+		}
+	}
+
+	return resources.ProfileAvatarsCollection{
+		Data: data,
+	}, nil
+}
@@ -17,6 +17,7 @@ type Handlers interface {
 
 	GetProfileByUsername(w http.ResponseWriter, r *http.Request)
 	GetProfileByID(w http.ResponseWriter, r *http.Request)
+	GetProfilesBatch(w http.ResponseWriter, r *http.Request)
 
 	FilterProfiles(w http.ResponseWriter, r *http.Request)
 
@@ -25,6 +26,11 @@ type Handlers interface {
 
 	OenProfileUpdateSession(w http.ResponseWriter, r *http.Request)
 	DeleteUploadProfileAvatar(w http.ResponseWriter, r *http.Request)
+	DeleteUploadProfileBanner(w http.ResponseWriter, r *http.Request)
+	CancelProfileUpdateSession(w http.ResponseWriter, r *http.Request)
+
+	ListMyAvatars(w http.ResponseWriter, r *http.Request)
+	RestoreMyAvatar(w http.ResponseWriter, r *http.Request)
 }
 
 type Middlewares interface {
@@ -38,6 +44,9 @@ type Router struct {
 	handlers    Handlers
 	middlewares Middlewares
 	log         *logium.Logger
+
+	storagePath    string
+	storageHandler http.Handler
 }
 
 func New(
@@ -52,6 +61,12 @@ func New(
 	}
 }
 
+// MountStorage serves the object storage URLs under path, used by the filesystem storage driver.
+func (rt *Router) MountStorage(path string, handler http.Handler) {
+	rt.storagePath = path
+	rt.storageHandler = handler
+}
+
 type Config struct {
 	Port              string
 	TimeoutRead       time.Duration
@@ -70,17 +85,22 @@ func (rt *Router) Run(ctx context.Context, cfg Config) {
 	// CORS for swagger UI documentation need to delete after configuring nginx
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"http://localhost:5002"},
-		AllowedMethods:   []string{"GET", "POST", "PATCH", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "If-Match"},
+		ExposedHeaders:   []string{"Link", "ETag"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
 
+	if rt.storageHandler != nil {
+		r.Mount(rt.storagePath, rt.storageHandler)
+	}
+
 	r.Route("/profiles-svc", func(r chi.Router) {
 		r.Route("/v1", func(r chi.Router) {
 			r.Route("/profiles", func(r chi.Router) {
 				r.Get("/", rt.handlers.FilterProfiles)
+				r.Post("/batch", rt.handlers.GetProfilesBatch)
 
 				r.Get("/u/{username}", rt.handlers.GetProfileByUsername)
 
@@ -89,9 +109,16 @@ func (rt *Router) Run(ctx context.Context, cfg Config) {
 
 					r.Route("/update-session", func(r chi.Router) {
 						r.Post("/", rt.handlers.OenProfileUpdateSession)
+						r.With(updateOwnProfile).Delete("/", rt.handlers.CancelProfileUpdateSession)
 
 						r.With(updateOwnProfile).Put("/confirm", rt.handlers.ConfirmUpdateMyProfile)
 						r.With(updateOwnProfile).Delete("/upload-avatar", rt.handlers.DeleteUploadProfileAvatar)
+						r.With(updateOwnProfile).Delete("/upload-banner", rt.handlers.DeleteUploadProfileBanner)
+					})
+
+					r.Route("/avatars", func(r chi.Router) {
+						r.Get("/", rt.handlers.ListMyAvatars)
+						r.Post("/{avatar_id}/restore", rt.handlers.RestoreMyAvatar)
 					})
 				})
 			})
@@ -0,0 +1,375 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// fsTempDir holds objects being written, they are renamed into place once complete.
+const fsTempDir = ".tmp"
+
+// fsListPageSize is the number of objects ListObjects passes to its callback at once,
+// as many as an S3 list page holds.
+const fsListPageSize = 1000
+
+// FS stores objects as files under a root directory. Presigned URLs point to
+// Handler and are signed with HMAC-SHA256, so it can stand in for S3 offline.
+type FS struct {
+	root      string
+	publicURL *url.URL
+	secret    []byte
+	maxSize   int64
+}
+
+type FSConfig struct {
+	// Root is the directory objects are stored in, it is created if missing.
+	Root string
+	// PublicURL is where Handler is reachable, e.g. http://localhost:8002/storage.
+	PublicURL string
+	// SecretKey signs the upload and download URLs.
+	SecretKey string
+	// MaxObjectSize caps an upload through Handler, zero means no limit.
+	MaxObjectSize int64
+}
+
+func NewFS(cfg FSConfig) (*FS, error) {
+	if cfg.SecretKey == "" {
+		return nil, fmt.Errorf("fs storage secret key is required")
+	}
+
+	publicURL, err := url.Parse(strings.TrimSuffix(cfg.PublicURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid fs storage public url %q: %w", cfg.PublicURL, err)
+	}
+	if publicURL.Path == "" {
+		// the handler shares the router with the API, it needs its own path
+		return nil, fmt.Errorf("fs storage public url %q must have a path", cfg.PublicURL)
+	}
+
+	root, err := filepath.Abs(cfg.Root)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fs storage root %q: %w", cfg.Root, err)
+	}
+	if err = os.MkdirAll(filepath.Join(root, fsTempDir), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create fs storage root %s: %w", root, err)
+	}
+
+	return &FS{
+		root:      root,
+		publicURL: publicURL,
+		secret:    []byte(cfg.SecretKey),
+		maxSize:   cfg.MaxObjectSize,
+	}, nil
+}
+
+// MountPath is the router path Handler has to be mounted on.
+func (s *FS) MountPath() string {
+	return s.publicURL.Path
+}
+
+func (s *FS) path(key string) (string, error) {
+	clean := path.Clean("/" + key)[1:]
+	if clean == "" || clean != key || strings.HasPrefix(clean, fsTempDir+"/") {
+		return "", fmt.Errorf("invalid object key %q", key)
+	}
+
+	return filepath.Join(s.root, filepath.FromSlash(clean)), nil
+}
+
+func (s *FS) sign(method, key string, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(method + "\n" + key + "\n" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *FS) signedURL(method, key string, expires int64) string {
+	u := *s.publicURL
+	u.Path = s.publicURL.Path + "/" + key
+	u.RawQuery = url.Values{
+		"expires":   {strconv.FormatInt(expires, 10)},
+		"signature": {s.sign(method, key, expires)},
+	}.Encode()
+
+	return u.String()
+}
+
+func (s *FS) PresignPut(
+	_ context.Context,
+	key string,
+	ttl time.Duration,
+) (uploadURL, getURL string, err error) {
+	if _, err = s.path(key); err != nil {
+		return "", "", err
+	}
+
+	expires := time.Now().Add(ttl).Unix()
+	return s.signedURL(http.MethodPut, key, expires), s.signedURL(http.MethodGet, key, expires), nil
+}
+
+// PresignGet returns a signed GET URL for the object that expires after ttl.
+func (s *FS) PresignGet(_ context.Context, key string, ttl time.Duration) (string, error) {
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+
+	return s.signedURL(http.MethodGet, key, time.Now().Add(ttl).Unix()), nil
+}
+
+func (s *FS) GetObject(_ context.Context, key string) (io.ReadCloser, int64, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	f, err := os.Open(p)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil, 0, fmt.Errorf("%w: %s", ErrNotFound, key)
+	case err != nil:
+		return nil, 0, fmt.Errorf("failed to open object %s: %w", key, err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, 0, fmt.Errorf("failed to stat object %s: %w", key, err)
+	}
+
+	return f, info.Size(), nil
+}
+
+// GetObjectRange returns the first bytes of the object and the size of the whole object.
+func (s *FS) GetObjectRange(ctx context.Context, key string, bytes int64) (io.ReadCloser, int64, error) {
+	body, size, err := s.GetObject(ctx, key)
+	if err != nil || bytes <= 0 {
+		return body, size, err
+	}
+
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(body, bytes), body}, size, nil
+}
+
+func (s *FS) PutObject(_ context.Context, key string, body io.Reader, _ int64, _ string) error {
+	_, err := s.write(key, body, -1)
+	return err
+}
+
+// write stores body under key through a temp file, so that readers never see
+// a partial object. A positive limit rejects bodies longer than limit bytes.
+func (s *FS) write(key string, body io.Reader, limit int64) (int64, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Join(s.root, fsTempDir), "object-*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create temp file for object %s: %w", key, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if limit > 0 {
+		body = io.LimitReader(body, limit+1)
+	}
+
+	n, err := io.Copy(tmp, body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	switch {
+	case err != nil:
+		return n, fmt.Errorf("failed to write object %s: %w", key, err)
+	case limit > 0 && n > limit:
+		return n, fmt.Errorf("object %s exceeds %d bytes", key, limit)
+	}
+
+	if err = os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return n, fmt.Errorf("failed to create directory for object %s: %w", key, err)
+	}
+	if err = os.Rename(tmp.Name(), p); err != nil {
+		return n, fmt.Errorf("failed to store object %s: %w", key, err)
+	}
+
+	return n, nil
+}
+
+func (s *FS) CopyObject(ctx context.Context, fromKey, toKey string) (string, error) {
+	body, _, err := s.GetObject(ctx, fromKey)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+
+	if _, err = s.write(toKey, body, -1); err != nil {
+		return "", err
+	}
+
+	return toKey, nil
+}
+
+// DeleteObject removes the object, deleting a missing object is not an error, as with S3.
+func (s *FS) DeleteObject(_ context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err = os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete object %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// ListObjects calls fn with every page of objects whose key starts with prefix,
+// pages hold up to fsListPageSize objects. Only the directory of prefix is walked.
+// An error returned by fn stops the listing.
+func (s *FS) ListObjects(ctx context.Context, prefix string, fn func(objects []Object) error) error {
+	dir := s.root
+	if i := strings.LastIndex(prefix, "/"); i >= 0 {
+		dir = filepath.Join(s.root, filepath.FromSlash(prefix[:i]))
+	}
+
+	page := make([]Object, 0, fsListPageSize)
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && p == dir {
+			return filepath.SkipAll
+		}
+		if err != nil {
+			return err
+		}
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p == filepath.Join(s.root, fsTempDir) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.root, p)
+		if err != nil {
+			return err
+		}
+
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		page = append(page, Object{
+			Key:          key,
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+		})
+		if len(page) < fsListPageSize {
+			return nil
+		}
+
+		err = fn(page)
+		page = make([]Object, 0, fsListPageSize)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list objects with prefix %s: %w", prefix, err)
+	}
+
+	if len(page) > 0 {
+		return fn(page)
+	}
+
+	return nil
+}
+
+// Handler serves the presigned URLs: PUT uploads an object, GET and HEAD download it.
+func (s *FS) Handler() http.Handler {
+	return http.StripPrefix(s.MountPath(), http.HandlerFunc(s.serve))
+}
+
+func (s *FS) serve(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, "/")
+
+	method := r.Method
+	if method == http.MethodHead {
+		method = http.MethodGet
+	}
+	if method != http.MethodGet && method != http.MethodPut {
+		w.Header().Set("Allow", "GET, HEAD, PUT")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	expires, err := strconv.ParseInt(r.URL.Query().Get("expires"), 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		http.Error(w, "url expired", http.StatusForbidden)
+		return
+	}
+
+	signature, err := hex.DecodeString(r.URL.Query().Get("signature"))
+	if err != nil {
+		http.Error(w, "invalid signature", http.StatusForbidden)
+		return
+	}
+	expected, _ := hex.DecodeString(s.sign(method, key, expires))
+	if !hmac.Equal(signature, expected) {
+		http.Error(w, "invalid signature", http.StatusForbidden)
+		return
+	}
+
+	switch method {
+	case http.MethodPut:
+		if s.maxSize > 0 && r.ContentLength > s.maxSize {
+			http.Error(w, "object too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		if _, err = s.write(key, r.Body, s.maxSize); err != nil {
+			http.Error(w, "failed to store object", http.StatusBadRequest)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet:
+		body, _, err := s.GetObject(r.Context(), key)
+		switch {
+		case errors.Is(err, ErrNotFound):
+			http.Error(w, "object not found", http.StatusNotFound)
+			return
+		case err != nil:
+			http.Error(w, "failed to read object", http.StatusInternalServerError)
+			return
+		}
+		defer body.Close()
+
+		f := body.(*os.File)
+		info, err := f.Stat()
+		if err != nil {
+			http.Error(w, "failed to read object", http.StatusInternalServerError)
+			return
+		}
+
+		http.ServeContent(w, r, path.Base(key), info.ModTime(), f)
+	}
+}
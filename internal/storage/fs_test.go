@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFSListObjects(t *testing.T) {
+	s, err := NewFS(FSConfig{
+		Root:      t.TempDir(),
+		PublicURL: "http://localhost/storage",
+		SecretKey: "secret",
+	})
+	if err != nil {
+		t.Fatalf("NewFS() error = %v", err)
+	}
+
+	ctx := context.Background()
+	keys := []string{"profile/avatar/a/1.png", "profile/banner/a/1.png"}
+	for i := 0; i < fsListPageSize+5; i++ {
+		keys = append(keys, fmt.Sprintf("profile/tmp/avatar/a/%04d", i))
+	}
+	for _, key := range keys {
+		if err = s.PutObject(ctx, key, strings.NewReader("x"), 1, "image/png"); err != nil {
+			t.Fatalf("PutObject(%q) error = %v", key, err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		prefix string
+		want   int
+		pages  int
+	}{
+		{name: "temp uploads over two pages", prefix: "profile/tmp/", want: fsListPageSize + 5, pages: 2},
+		{name: "one kind", prefix: "profile/avatar/", want: 1, pages: 1},
+		{name: "partial segment", prefix: "profile/ban", want: 1, pages: 1},
+		{name: "missing directory", prefix: "quarantine/", want: 0, pages: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			pages := 0
+			err := s.ListObjects(ctx, tt.prefix, func(objects []Object) error {
+				pages++
+				for _, obj := range objects {
+					if !strings.HasPrefix(obj.Key, tt.prefix) {
+						t.Errorf("listed key %q outside of prefix %q", obj.Key, tt.prefix)
+					}
+					got = append(got, obj.Key)
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("ListObjects() error = %v", err)
+			}
+			if len(got) != tt.want || pages != tt.pages {
+				t.Fatalf("ListObjects() listed %d objects in %d pages, want %d in %d", len(got), pages, tt.want, tt.pages)
+			}
+		})
+	}
+}
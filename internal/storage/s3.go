@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/netbill/awsx"
+)
+
+// S3 is an awsx bucket extended with the operations awsx does not provide.
+type S3 struct {
+	*awsx.Bucket
+
+	name    string
+	client  *s3.Client
+	presign *s3.PresignClient
+}
+
+func NewS3(name string, client *s3.Client, presign *s3.PresignClient) *S3 {
+	return &S3{
+		Bucket:  awsx.New(name, client, presign),
+		name:    name,
+		client:  client,
+		presign: presign,
+	}
+}
+
+// PresignGet returns a GET URL for the object that expires after ttl.
+func (s *S3) PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	out, err := s.presign.PresignGetObject(
+		ctx,
+		&s3.GetObjectInput{
+			Bucket: aws.String(s.name),
+			Key:    aws.String(key),
+		},
+		s3.WithPresignExpires(ttl),
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign get object %s: %w", key, err)
+	}
+
+	return out.URL, nil
+}
+
+// GetObject is awsx GetObject with a missing key reported as ErrNotFound.
+func (s *S3) GetObject(ctx context.Context, key string) (io.ReadCloser, int64, error) {
+	body, size, err := s.Bucket.GetObject(ctx, key)
+	if isNoSuchKey(err) {
+		return nil, 0, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+
+	return body, size, err
+}
+
+// GetObjectRange is awsx GetObjectRange with a missing key reported as ErrNotFound.
+func (s *S3) GetObjectRange(ctx context.Context, key string, bytes int64) (io.ReadCloser, int64, error) {
+	body, size, err := s.Bucket.GetObjectRange(ctx, key, bytes)
+	if isNoSuchKey(err) {
+		return nil, 0, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+
+	return body, size, err
+}
+
+func isNoSuchKey(err error) bool {
+	var noSuchKey *types.NoSuchKey
+	var notFound *types.NotFound
+	return errors.As(err, &noSuchKey) || errors.As(err, &notFound)
+}
+
+// ListObjects calls fn with every page of objects whose key starts with prefix,
+// one page is held in memory at a time. An error returned by fn stops the listing.
+func (s *S3) ListObjects(ctx context.Context, prefix string, fn func(objects []Object) error) error {
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.name),
+		Prefix: aws.String(prefix),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list objects with prefix %s: %w", prefix, err)
+		}
+
+		objects := make([]Object, 0, len(page.Contents))
+		for _, obj := range page.Contents {
+			o := Object{
+				Key:  aws.ToString(obj.Key),
+				Size: aws.ToInt64(obj.Size),
+			}
+			if obj.LastModified != nil {
+				o.LastModified = *obj.LastModified
+			}
+			objects = append(objects, o)
+		}
+
+		if err = fn(objects); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *S3) PutObject(
+	ctx context.Context,
+	key string,
+	body io.Reader,
+	size int64,
+	contentType string,
+) error {
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(s.name),
+		Key:           aws.String(key),
+		Body:          body,
+		ContentLength: aws.Int64(size),
+		ContentType:   aws.String(contentType),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put object %s: %w", key, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,15 @@
+package storage
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned when the requested object does not exist.
+var ErrNotFound = errors.New("object not found")
+
+type Object struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
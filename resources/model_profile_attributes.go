@@ -32,6 +32,10 @@ type ProfileAttributes struct {
 	Official bool `json:"official"`
 	// Avatar URL
 	Avatar *string `json:"avatar,omitempty"`
+	// Resized avatar copies by variant name, e.g. 128_jpeg
+	AvatarVariants *map[string]string `json:"avatar_variants,omitempty"`
+	// Banner URL
+	Banner *string `json:"banner,omitempty"`
 	// Updated At
 	UpdatedAt time.Time `json:"updated_at"`
 	// Created At
@@ -205,6 +209,70 @@ func (o *ProfileAttributes) SetAvatar(v string) {
 	o.Avatar = &v
 }
 
+// GetAvatarVariants returns the AvatarVariants field value if set, zero value otherwise.
+func (o *ProfileAttributes) GetAvatarVariants() map[string]string {
+	if o == nil || IsNil(o.AvatarVariants) {
+		var ret map[string]string
+		return ret
+	}
+	return *o.AvatarVariants
+}
+
+// GetAvatarVariantsOk returns a tuple with the AvatarVariants field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *ProfileAttributes) GetAvatarVariantsOk() (*map[string]string, bool) {
+	if o == nil || IsNil(o.AvatarVariants) {
+		return &map[string]string{}, false
+	}
+	return o.AvatarVariants, true
+}
+
+// HasAvatarVariants returns a boolean if a field has been set.
+func (o *ProfileAttributes) HasAvatarVariants() bool {
+	if o != nil && !IsNil(o.AvatarVariants) {
+		return true
+	}
+
+	return false
+}
+
+// SetAvatarVariants gets a reference to the given map[string]string and assigns it to the AvatarVariants field.
+func (o *ProfileAttributes) SetAvatarVariants(v map[string]string) {
+	o.AvatarVariants = &v
+}
+
+// GetBanner returns the Banner field value if set, zero value otherwise.
+func (o *ProfileAttributes) GetBanner() string {
+	if o == nil || IsNil(o.Banner) {
+		var ret string
+		return ret
+	}
+	return *o.Banner
+}
+
+// GetBannerOk returns a tuple with the Banner field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *ProfileAttributes) GetBannerOk() (*string, bool) {
+	if o == nil || IsNil(o.Banner) {
+		return nil, false
+	}
+	return o.Banner, true
+}
+
+// HasBanner returns a boolean if a field has been set.
+func (o *ProfileAttributes) HasBanner() bool {
+	if o != nil && !IsNil(o.Banner) {
+		return true
+	}
+
+	return false
+}
+
+// SetBanner gets a reference to the given string and assigns it to the Banner field.
+func (o *ProfileAttributes) SetBanner(v string) {
+	o.Banner = &v
+}
+
 // GetUpdatedAt returns the UpdatedAt field value
 func (o *ProfileAttributes) GetUpdatedAt() time.Time {
 	if o == nil {
@@ -274,6 +342,12 @@ func (o ProfileAttributes) ToMap() (map[string]interface{}, error) {
 	if !IsNil(o.Avatar) {
 		toSerialize["avatar"] = o.Avatar
 	}
+	if !IsNil(o.AvatarVariants) {
+		toSerialize["avatar_variants"] = o.AvatarVariants
+	}
+	if !IsNil(o.Banner) {
+		toSerialize["banner"] = o.Banner
+	}
 	toSerialize["updated_at"] = o.UpdatedAt
 	toSerialize["created_at"] = o.CreatedAt
 	return toSerialize, nil
@@ -23,6 +23,7 @@ var _ MappedNullable = &ProfilesCollection{}
 type ProfilesCollection struct {
 	Data []ProfileData `json:"data"`
 	Links PaginationData `json:"links"`
+	Meta *ProfilesCollectionMeta `json:"meta,omitempty"`
 }
 
 type _ProfilesCollection ProfilesCollection
@@ -94,6 +95,38 @@ func (o *ProfilesCollection) SetLinks(v PaginationData) {
 	o.Links = v
 }
 
+// GetMeta returns the Meta field value if set, zero value otherwise.
+func (o *ProfilesCollection) GetMeta() ProfilesCollectionMeta {
+	if o == nil || IsNil(o.Meta) {
+		var ret ProfilesCollectionMeta
+		return ret
+	}
+	return *o.Meta
+}
+
+// GetMetaOk returns a tuple with the Meta field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *ProfilesCollection) GetMetaOk() (*ProfilesCollectionMeta, bool) {
+	if o == nil || IsNil(o.Meta) {
+		return nil, false
+	}
+	return o.Meta, true
+}
+
+// HasMeta returns a boolean if a field has been set.
+func (o *ProfilesCollection) HasMeta() bool {
+	if o != nil && !IsNil(o.Meta) {
+		return true
+	}
+
+	return false
+}
+
+// SetMeta gets a reference to the given ProfilesCollectionMeta and assigns it to the Meta field.
+func (o *ProfilesCollection) SetMeta(v ProfilesCollectionMeta) {
+	o.Meta = &v
+}
+
 func (o ProfilesCollection) MarshalJSON() ([]byte, error) {
 	toSerialize,err := o.ToMap()
 	if err != nil {
@@ -106,6 +139,9 @@ func (o ProfilesCollection) ToMap() (map[string]interface{}, error) {
 	toSerialize := map[string]interface{}{}
 	toSerialize["data"] = o.Data
 	toSerialize["links"] = o.Links
+	if !IsNil(o.Meta) {
+		toSerialize["meta"] = o.Meta
+	}
 	return toSerialize, nil
 }
 
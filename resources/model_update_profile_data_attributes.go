@@ -21,12 +21,14 @@ var _ MappedNullable = &UpdateProfileDataAttributes{}
 
 // UpdateProfileDataAttributes struct for UpdateProfileDataAttributes
 type UpdateProfileDataAttributes struct {
-	// pseudonym
-	Pseudonym *string `json:"pseudonym,omitempty"`
-	// description
-	Description *string `json:"description,omitempty"`
+	// pseudonym, null clears it
+	Pseudonym NullableString `json:"pseudonym,omitempty"`
+	// description, null clears it
+	Description NullableString `json:"description,omitempty"`
 	// delete avatar
 	DeleteAvatar bool `json:"delete_avatar"`
+	// delete banner
+	DeleteBanner *bool `json:"delete_banner,omitempty"`
 }
 
 type _UpdateProfileDataAttributes UpdateProfileDataAttributes
@@ -49,68 +51,88 @@ func NewUpdateProfileDataAttributesWithDefaults() *UpdateProfileDataAttributes {
 	return &this
 }
 
-// GetPseudonym returns the Pseudonym field value if set, zero value otherwise.
+// GetPseudonym returns the Pseudonym field value if set, zero value otherwise (both if not set or set to explicit null).
 func (o *UpdateProfileDataAttributes) GetPseudonym() string {
-	if o == nil || IsNil(o.Pseudonym) {
+	if o == nil || IsNil(o.Pseudonym.Get()) {
 		var ret string
 		return ret
 	}
-	return *o.Pseudonym
+	return *o.Pseudonym.Get()
 }
 
 // GetPseudonymOk returns a tuple with the Pseudonym field value if set, nil otherwise
 // and a boolean to check if the value has been set.
+// NOTE: If the value is an explicit nil, `nil, true` will be returned
 func (o *UpdateProfileDataAttributes) GetPseudonymOk() (*string, bool) {
-	if o == nil || IsNil(o.Pseudonym) {
+	if o == nil {
 		return nil, false
 	}
-	return o.Pseudonym, true
+	return o.Pseudonym.Get(), o.Pseudonym.IsSet()
 }
 
 // HasPseudonym returns a boolean if a field has been set.
 func (o *UpdateProfileDataAttributes) HasPseudonym() bool {
-	if o != nil && !IsNil(o.Pseudonym) {
+	if o != nil && o.Pseudonym.IsSet() {
 		return true
 	}
 
 	return false
 }
 
-// SetPseudonym gets a reference to the given string and assigns it to the Pseudonym field.
+// SetPseudonym gets a reference to the given NullableString and assigns it to the Pseudonym field.
 func (o *UpdateProfileDataAttributes) SetPseudonym(v string) {
-	o.Pseudonym = &v
+	o.Pseudonym.Set(&v)
+}
+// SetPseudonymNil sets the value for Pseudonym to be an explicit nil
+func (o *UpdateProfileDataAttributes) SetPseudonymNil() {
+	o.Pseudonym.Set(nil)
+}
+
+// UnsetPseudonym ensures that no value is present for Pseudonym, not even an explicit nil
+func (o *UpdateProfileDataAttributes) UnsetPseudonym() {
+	o.Pseudonym.Unset()
 }
 
-// GetDescription returns the Description field value if set, zero value otherwise.
+// GetDescription returns the Description field value if set, zero value otherwise (both if not set or set to explicit null).
 func (o *UpdateProfileDataAttributes) GetDescription() string {
-	if o == nil || IsNil(o.Description) {
+	if o == nil || IsNil(o.Description.Get()) {
 		var ret string
 		return ret
 	}
-	return *o.Description
+	return *o.Description.Get()
 }
 
 // GetDescriptionOk returns a tuple with the Description field value if set, nil otherwise
 // and a boolean to check if the value has been set.
+// NOTE: If the value is an explicit nil, `nil, true` will be returned
 func (o *UpdateProfileDataAttributes) GetDescriptionOk() (*string, bool) {
-	if o == nil || IsNil(o.Description) {
+	if o == nil {
 		return nil, false
 	}
-	return o.Description, true
+	return o.Description.Get(), o.Description.IsSet()
 }
 
 // HasDescription returns a boolean if a field has been set.
 func (o *UpdateProfileDataAttributes) HasDescription() bool {
-	if o != nil && !IsNil(o.Description) {
+	if o != nil && o.Description.IsSet() {
 		return true
 	}
 
 	return false
 }
 
-// SetDescription gets a reference to the given string and assigns it to the Description field.
+// SetDescription gets a reference to the given NullableString and assigns it to the Description field.
 func (o *UpdateProfileDataAttributes) SetDescription(v string) {
-	o.Description = &v
+	o.Description.Set(&v)
+}
+// SetDescriptionNil sets the value for Description to be an explicit nil
+func (o *UpdateProfileDataAttributes) SetDescriptionNil() {
+	o.Description.Set(nil)
+}
+
+// UnsetDescription ensures that no value is present for Description, not even an explicit nil
+func (o *UpdateProfileDataAttributes) UnsetDescription() {
+	o.Description.Unset()
 }
 
 // GetDeleteAvatar returns the DeleteAvatar field value
@@ -137,6 +159,38 @@ func (o *UpdateProfileDataAttributes) SetDeleteAvatar(v bool) {
 	o.DeleteAvatar = v
 }
 
+// GetDeleteBanner returns the DeleteBanner field value if set, zero value otherwise.
+func (o *UpdateProfileDataAttributes) GetDeleteBanner() bool {
+	if o == nil || IsNil(o.DeleteBanner) {
+		var ret bool
+		return ret
+	}
+	return *o.DeleteBanner
+}
+
+// GetDeleteBannerOk returns a tuple with the DeleteBanner field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *UpdateProfileDataAttributes) GetDeleteBannerOk() (*bool, bool) {
+	if o == nil || IsNil(o.DeleteBanner) {
+		return nil, false
+	}
+	return o.DeleteBanner, true
+}
+
+// HasDeleteBanner returns a boolean if a field has been set.
+func (o *UpdateProfileDataAttributes) HasDeleteBanner() bool {
+	if o != nil && !IsNil(o.DeleteBanner) {
+		return true
+	}
+
+	return false
+}
+
+// SetDeleteBanner gets a reference to the given bool and assigns it to the DeleteBanner field.
+func (o *UpdateProfileDataAttributes) SetDeleteBanner(v bool) {
+	o.DeleteBanner = &v
+}
+
 func (o UpdateProfileDataAttributes) MarshalJSON() ([]byte, error) {
 	toSerialize,err := o.ToMap()
 	if err != nil {
@@ -147,13 +201,16 @@ func (o UpdateProfileDataAttributes) MarshalJSON() ([]byte, error) {
 
 func (o UpdateProfileDataAttributes) ToMap() (map[string]interface{}, error) {
 	toSerialize := map[string]interface{}{}
-	if !IsNil(o.Pseudonym) {
-		toSerialize["pseudonym"] = o.Pseudonym
+	if o.Pseudonym.IsSet() {
+		toSerialize["pseudonym"] = o.Pseudonym.Get()
 	}
-	if !IsNil(o.Description) {
-		toSerialize["description"] = o.Description
+	if o.Description.IsSet() {
+		toSerialize["description"] = o.Description.Get()
 	}
 	toSerialize["delete_avatar"] = o.DeleteAvatar
+	if !IsNil(o.DeleteBanner) {
+		toSerialize["delete_banner"] = o.DeleteBanner
+	}
 	return toSerialize, nil
 }
 
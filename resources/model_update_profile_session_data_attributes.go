@@ -27,6 +27,10 @@ type UpdateProfileSessionDataAttributes struct {
 	UploadUrl string `json:"upload_url"`
 	// Pre-signed GET URL to read uploaded avatar
 	GetUrl string `json:"get_url"`
+	// Pre-signed PUT URL for banner upload
+	BannerUploadUrl string `json:"banner_upload_url"`
+	// Pre-signed GET URL to read uploaded banner
+	BannerGetUrl string `json:"banner_get_url"`
 }
 
 type _UpdateProfileSessionDataAttributes UpdateProfileSessionDataAttributes
@@ -35,11 +39,13 @@ type _UpdateProfileSessionDataAttributes UpdateProfileSessionDataAttributes
 // This constructor will assign default values to properties that have it defined,
 // and makes sure properties required by API are set, but the set of arguments
 // will change when the set of required properties is changed
-func NewUpdateProfileSessionDataAttributes(uploadToken string, uploadUrl string, getUrl string) *UpdateProfileSessionDataAttributes {
+func NewUpdateProfileSessionDataAttributes(uploadToken string, uploadUrl string, getUrl string, bannerUploadUrl string, bannerGetUrl string) *UpdateProfileSessionDataAttributes {
 	this := UpdateProfileSessionDataAttributes{}
 	this.UploadToken = uploadToken
 	this.UploadUrl = uploadUrl
 	this.GetUrl = getUrl
+	this.BannerUploadUrl = bannerUploadUrl
+	this.BannerGetUrl = bannerGetUrl
 	return &this
 }
 
@@ -123,6 +129,54 @@ func (o *UpdateProfileSessionDataAttributes) SetGetUrl(v string) {
 	o.GetUrl = v
 }
 
+// GetBannerUploadUrl returns the BannerUploadUrl field value
+func (o *UpdateProfileSessionDataAttributes) GetBannerUploadUrl() string {
+	if o == nil {
+		var ret string
+		return ret
+	}
+
+	return o.BannerUploadUrl
+}
+
+// GetBannerUploadUrlOk returns a tuple with the BannerUploadUrl field value
+// and a boolean to check if the value has been set.
+func (o *UpdateProfileSessionDataAttributes) GetBannerUploadUrlOk() (*string, bool) {
+	if o == nil {
+		return nil, false
+	}
+	return &o.BannerUploadUrl, true
+}
+
+// SetBannerUploadUrl sets field value
+func (o *UpdateProfileSessionDataAttributes) SetBannerUploadUrl(v string) {
+	o.BannerUploadUrl = v
+}
+
+// GetBannerGetUrl returns the BannerGetUrl field value
+func (o *UpdateProfileSessionDataAttributes) GetBannerGetUrl() string {
+	if o == nil {
+		var ret string
+		return ret
+	}
+
+	return o.BannerGetUrl
+}
+
+// GetBannerGetUrlOk returns a tuple with the BannerGetUrl field value
+// and a boolean to check if the value has been set.
+func (o *UpdateProfileSessionDataAttributes) GetBannerGetUrlOk() (*string, bool) {
+	if o == nil {
+		return nil, false
+	}
+	return &o.BannerGetUrl, true
+}
+
+// SetBannerGetUrl sets field value
+func (o *UpdateProfileSessionDataAttributes) SetBannerGetUrl(v string) {
+	o.BannerGetUrl = v
+}
+
 func (o UpdateProfileSessionDataAttributes) MarshalJSON() ([]byte, error) {
 	toSerialize,err := o.ToMap()
 	if err != nil {
@@ -136,6 +190,8 @@ func (o UpdateProfileSessionDataAttributes) ToMap() (map[string]interface{}, err
 	toSerialize["upload_token"] = o.UploadToken
 	toSerialize["upload_url"] = o.UploadUrl
 	toSerialize["get_url"] = o.GetUrl
+	toSerialize["banner_upload_url"] = o.BannerUploadUrl
+	toSerialize["banner_get_url"] = o.BannerGetUrl
 	return toSerialize, nil
 }
 
@@ -147,6 +203,8 @@ func (o *UpdateProfileSessionDataAttributes) UnmarshalJSON(data []byte) (err err
 		"upload_token",
 		"upload_url",
 		"get_url",
+		"banner_upload_url",
+		"banner_get_url",
 	}
 
 	allProperties := make(map[string]interface{})